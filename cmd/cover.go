@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/thesephist/xin/pkg/xin"
+)
+
+var coverHTMLPath string
+
+var coverCmd = &cobra.Command{
+	Use:   "cover [profile]",
+	Short: "Show a coverage profile as annotated source",
+	Long: "Cover reads a coverage profile written with --coverage, and prints the source of each " +
+		"covered file, including the standard library, annotated with how often each line was evaluated.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file, err := os.Open(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+
+		coverage, err := xin.ReadCoverageProfile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %s\n", args[0], err)
+			os.Exit(1)
+		}
+
+		if coverHTMLPath == "" {
+			err = coverage.WriteText(os.Stdout)
+		} else {
+			var out *os.File
+			out, err = os.Create(coverHTMLPath)
+			if err == nil {
+				err = coverage.WriteHTML(out)
+				out.Close()
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	coverCmd.Flags().StringVar(&coverHTMLPath, "html", "",
+		"write the annotated source as an HTML page to this file")
+	rootCmd.AddCommand(coverCmd)
+}
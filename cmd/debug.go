@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/thesephist/xin/pkg/xin"
+)
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Start a debug adapter on standard input and output",
+	Long: "Debug speaks the Debug Adapter Protocol over standard input and output, " +
+		"so editors can launch and debug Xin programs with breakpoints and stepping.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// standard output carries the protocol,
+		// so errors can only go to standard error
+		if err := xin.ServeDebugAdapter(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error in debug adapter: %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(debugCmd)
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/thesephist/xin/pkg/xin"
+)
+
+var fmtCheck bool
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [files]",
+	Short: "Format Xin programs",
+	Long: "Fmt rewrites Xin programs in the canonical layout of the standard library. " +
+		"Directories are formatted recursively, and with no files, fmt formats standard input to standard output.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			if !formatStdin() {
+				os.Exit(1)
+			}
+			return
+		}
+
+		ok := true
+		for _, arg := range args {
+			paths, err := xinFiles(arg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+				ok = false
+				continue
+			}
+
+			for _, path := range paths {
+				if !formatFile(path) {
+					ok = false
+				}
+			}
+		}
+		if !ok {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	fmtCmd.Flags().BoolVar(&fmtCheck, "check", false,
+		"list files that are not formatted, without rewriting them, and fail if there are any")
+	rootCmd.AddCommand(fmtCmd)
+}
+
+// xinFiles returns the .xin files under a path, or the path itself if
+// it is a file.
+func xinFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	paths := []string{}
+	err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.HasSuffix(p, ".xin") {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// formatFile formats the file at path in place, or with --check
+// reports it if it is not formatted. It returns false on failure.
+func formatFile(path string) bool {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return false
+	}
+
+	formatted, ierr := xin.FormatSource(path, string(src))
+	if ierr != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", xin.FormatError(ierr))
+		return false
+	}
+	if formatted == string(src) {
+		return true
+	}
+
+	if fmtCheck {
+		fmt.Println(path)
+		return false
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return false
+	}
+	if err := ioutil.WriteFile(path, []byte(formatted), info.Mode()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return false
+	}
+	return true
+}
+
+func formatStdin() bool {
+	src, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return false
+	}
+
+	formatted, ierr := xin.FormatSource("stdin", string(src))
+	if ierr != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", xin.FormatError(ierr))
+		return false
+	}
+
+	if fmtCheck {
+		return formatted == string(src)
+	}
+	fmt.Print(formatted)
+	return true
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/thesephist/xin/pkg/xin"
+)
+
+var lintJSON bool
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [files]",
+	Short: "Check Xin programs for common mistakes",
+	Long: "Lint analyzes Xin programs without running them, and reports undefined names, calls with " +
+		"the wrong number of arguments, invalid if forms, unused bindings and shadowed builtins. " +
+		"Directories are linted recursively, and with no files, lint checks standard input.",
+	Run: func(cmd *cobra.Command, args []string) {
+		linter, err := xin.NewLinter()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+
+		issues := []xin.LintIssue{}
+		ok := true
+		if len(args) == 0 {
+			src, err := ioutil.ReadAll(os.Stdin)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+				os.Exit(1)
+			}
+			issues = linter.Lint("stdin", string(src))
+		}
+		for _, arg := range args {
+			paths, err := xinFiles(arg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+				ok = false
+				continue
+			}
+
+			for _, path := range paths {
+				src, err := ioutil.ReadFile(path)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+					ok = false
+					continue
+				}
+				issues = append(issues, linter.Lint(path, string(src))...)
+			}
+		}
+
+		if lintJSON {
+			out, _ := json.MarshalIndent(issues, "", "  ")
+			fmt.Println(string(out))
+		} else {
+			for _, issue := range issues {
+				fmt.Println(issue)
+			}
+		}
+
+		if !ok || len(issues) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	lintCmd.Flags().BoolVar(&lintJSON, "json", false,
+		"print issues as a JSON array, for tools")
+	rootCmd.AddCommand(lintCmd)
+}
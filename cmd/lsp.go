@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/thesephist/xin/pkg/xin"
+)
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Start a language server on standard input and output",
+	Long: "Lsp speaks the Language Server Protocol over standard input and output, " +
+		"so editors can show diagnostics, definitions, hovers and completions for Xin programs.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// standard output carries the protocol,
+		// so errors can only go to standard error
+		if err := xin.ServeLanguageServer(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error in language server: %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+}
@@ -2,28 +2,53 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"strings"
+	"sync"
 
 	"github.com/fatih/color"
 	"github.com/thesephist/xin/pkg/xin"
 )
 
-func repl() {
-	vm, err := xin.NewVm()
+func repl() int {
+	// repl inputs have no source file for a coverage report
+	// to show, so coverage is only recorded for programs
+	if coveragePath != "" {
+		color.Red("Error: --coverage needs a program to run, and cannot be used in the repl\n")
+		return 1
+	}
+
+	vm, err := newVm()
 	if err != nil {
 		color.Red("Error creating Xin VM: %s\n", xin.FormatError(err))
-		return
+		return 1
 	}
 
+	// SIGINT interrupts the input being evaluated, if any,
+	// and otherwise exits the repl as usual
+	var cancelMu sync.Mutex
+	var cancelEval context.CancelFunc
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+	go func() {
+		for range sigs {
+			cancelMu.Lock()
+			if cancelEval == nil {
+				fmt.Println()
+				os.Exit(0)
+			}
+			cancelEval()
+			cancelMu.Unlock()
+		}
+	}()
+
 	replCount := 0
 	reader := bufio.NewReader(os.Stdin)
 	for {
-		// TODO: capture SIGINT and just continue loop
-		// this requires running the VM in a Context{}
-
 		fmt.Printf("%d ) ", replCount)
 
 		text, err := reader.ReadString('\n')
@@ -34,7 +59,21 @@ func repl() {
 			continue
 		}
 
-		result, ierr := vm.Eval(fmt.Sprintf("input %d", replCount), strings.NewReader(text))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelMu.Lock()
+		cancelEval = cancel
+		cancelMu.Unlock()
+
+		result, ierr := vm.EvalContext(ctx, fmt.Sprintf("input %d", replCount), strings.NewReader(text))
+
+		cancelMu.Lock()
+		cancelEval = nil
+		cancelMu.Unlock()
+		cancel()
+
+		if exit, ok := ierr.(xin.ExitError); ok {
+			return exit.Code
+		}
 		if ierr != nil {
 			color.Red("Eval error: %s\n\n", xin.FormatError(ierr))
 			continue
@@ -48,4 +87,6 @@ func repl() {
 
 		replCount++
 	}
+
+	return 0
 }
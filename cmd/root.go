@@ -1,14 +1,21 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"strings"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/thesephist/xin/pkg/xin"
 )
 
 const version = "0.1"
 
+var backendName string
+var profilePath string
+var coveragePath string
+
 var rootCmd = &cobra.Command{
 	Use:   "xin [files]",
 	Short: "Run Xin programs",
@@ -17,27 +24,149 @@ var rootCmd = &cobra.Command{
 		"  xin\t\t\tstart a repl",
 		"  xin prog.xin\t\trun prog.xin",
 		"  echo file | xin\trun from stdin",
+		"  xin --backend bytecode prog.xin\trun prog.xin on the bytecode VM",
+		"  xin --profile prof.pb.gz prog.xin\tprofile prog.xin for go tool pprof",
+		"  xin --coverage cover.out prog.xin\trecord the code coverage of prog.xin",
+		"  xin cover cover.out\tshow coverage as annotated source",
+		"  xin debug\t\tstart a debug adapter for editors",
+		"  xin lsp\t\tstart a language server for editors",
+		"  xin fmt prog.xin\tformat prog.xin in place",
+		"  xin lint prog.xin\tcheck prog.xin for common mistakes",
+		"  xin test\t\trun the *_test.xin files under this directory",
 	}, "\n"),
 	Version: version,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if _, err := backend(); err != nil {
+			return err
+		}
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
+		// os::args should see the program and its arguments,
+		// but not flags meant for the interpreter
+		os.Args = append(os.Args[:1], args...)
+
 		// check if running files
 		if len(args) >= 1 {
-			run(args[0])
-			return
+			os.Exit(run(args[0]))
 		}
 
 		// check if there's stdin
 		stat, _ := os.Stdin.Stat()
 		if (stat.Mode() & os.ModeCharDevice) == 0 {
-			stdin()
-			return
+			os.Exit(stdin())
 		}
 
 		// if all else fails, start repl
-		repl()
+		os.Exit(repl())
 	},
 }
 
+func init() {
+	// xin test runs programs too, so it takes the backend flag
+	rootCmd.PersistentFlags().StringVar(&backendName, "backend", "tree",
+		"evaluator to run programs with, tree or bytecode")
+	rootCmd.Flags().StringVar(&profilePath, "profile", "",
+		"write a pprof profile of the time spent in each form to this file")
+	// xin test records coverage too
+	rootCmd.PersistentFlags().StringVar(&coveragePath, "coverage", "",
+		"write a profile of the forms and branches evaluated to this file")
+	// flags after the program path belong to the program
+	rootCmd.Flags().SetInterspersed(false)
+}
+
+func backend() (xin.Backend, error) {
+	switch backendName {
+	case "tree":
+		return xin.TreeWalkBackend, nil
+	case "bytecode":
+		return xin.BytecodeBackend, nil
+	default:
+		return 0, fmt.Errorf("unknown backend %s", backendName)
+	}
+}
+
+// newVm creates a Xin VM configured by command line flags
+func newVm() (*xin.Vm, xin.InterpreterError) {
+	// coverage starts before the standard library is
+	// loaded, so that its top level is covered
+	vm, err := xin.NewVmWithOptions(xin.VmOptions{
+		Coverage: coveragePath != "",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	b, _ := backend()
+	vm.SetBackend(b)
+
+	if profilePath != "" {
+		vm.StartProfile()
+	}
+
+	return vm, nil
+}
+
+// exitStatus reports the result of running a program, and returns the
+// exit status of the xin process: the status given to os::exit if the
+// program called it, or 1 if the program failed with an error, either
+// in its own evaluation or in an async callback.
+func exitStatus(vm *xin.Vm, err xin.InterpreterError) int {
+	if exit, ok := err.(xin.ExitError); ok {
+		return exit.Code
+	}
+
+	if err != nil {
+		color.Red("Error: %s\n", xin.FormatError(err))
+		return 1
+	}
+
+	// async errors were printed as they happened
+	if len(vm.AsyncErrors()) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// writeProfile writes the profile of a VM to the file given by
+// the --profile flag, if it was profiled.
+func writeProfile(vm *xin.Vm) {
+	profile := vm.StopProfile()
+	if profile == nil {
+		return
+	}
+
+	file, err := os.Create(profilePath)
+	if err != nil {
+		color.Red("Error writing profile: %s\n", err)
+		return
+	}
+	defer file.Close()
+
+	if err := profile.WritePprof(file); err != nil {
+		color.Red("Error writing profile: %s\n", err)
+	}
+}
+
+// writeCoverage writes a coverage profile to the file given by the
+// --coverage flag, if coverage was recorded.
+func writeCoverage(c *xin.Coverage) {
+	if c == nil {
+		return
+	}
+
+	file, err := os.Create(coveragePath)
+	if err != nil {
+		color.Red("Error writing coverage: %s\n", err)
+		return
+	}
+	defer file.Close()
+
+	if err := c.WriteProfile(file); err != nil {
+		color.Red("Error writing coverage: %s\n", err)
+	}
+}
+
 func Execute() error {
 	rootCmd.SetVersionTemplate(`{{with .Name}}{{printf "%s " .}}{{end}}{{printf "v%s" .Version}}
 `)
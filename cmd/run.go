@@ -5,15 +5,18 @@ import (
 	"github.com/thesephist/xin/pkg/xin"
 )
 
-func run(path string) {
-	vm, err := xin.NewVm()
+func run(path string) int {
+	vm, err := newVm()
 	if err != nil {
 		color.Red("Error creating Xin VM: %s\n", xin.FormatError(err))
-		return
+		return 1
 	}
 
+	defer writeProfile(vm)
+	defer func() {
+		writeCoverage(vm.StopCoverage())
+	}()
+
 	err = vm.Exec(path)
-	if err != nil {
-		color.Red("Error: %s\n", xin.FormatError(err))
-	}
+	return exitStatus(vm, err)
 }
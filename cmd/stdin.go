@@ -7,15 +7,18 @@ import (
 	"github.com/thesephist/xin/pkg/xin"
 )
 
-func stdin() {
-	vm, err := xin.NewVm()
+func stdin() int {
+	vm, err := newVm()
 	if err != nil {
 		color.Red("Error creating Xin VM: %s\n", xin.FormatError(err))
-		return
+		return 1
 	}
 
+	defer writeProfile(vm)
+	defer func() {
+		writeCoverage(vm.StopCoverage())
+	}()
+
 	_, err = vm.Eval("stdin", os.Stdin)
-	if err != nil {
-		color.Red("Error: %s\n", xin.FormatError(err))
-	}
+	return exitStatus(vm, err)
 }
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/thesephist/xin/pkg/xin"
+)
+
+var junitPath string
+var jsonPath string
+
+var testCmd = &cobra.Command{
+	Use:   "test [paths]",
+	Short: "Run Xin tests",
+	Long: "Test runs each *_test.xin file under the given paths, or the current directory, in a fresh VM, " +
+		"and fails if any test case fails or any test file raises an error.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, err := backend(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+
+		paths := []string{}
+		for _, arg := range args {
+			found, err := testFiles(arg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+				os.Exit(1)
+			}
+			paths = append(paths, found...)
+		}
+
+		results := make([]testFileResult, len(paths))
+		var coverage *xin.Coverage
+		for i, path := range paths {
+			var fileCoverage *xin.Coverage
+			results[i], fileCoverage = runTestFile(path)
+
+			if coverage == nil {
+				coverage = fileCoverage
+			} else if fileCoverage != nil {
+				coverage.Merge(fileCoverage)
+			}
+		}
+		writeCoverage(coverage)
+
+		if junitPath != "" {
+			if err := writeJUnit(junitPath, results); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing JUnit results: %s\n", err)
+				os.Exit(1)
+			}
+		}
+		if jsonPath != "" {
+			out, _ := json.MarshalIndent(results, "", "  ")
+			if err := ioutil.WriteFile(jsonPath, append(out, '\n'), 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing JSON results: %s\n", err)
+				os.Exit(1)
+			}
+		}
+
+		for _, result := range results {
+			if !result.Passed {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+func init() {
+	testCmd.Flags().StringVar(&junitPath, "junit", "",
+		"write results as JUnit XML to this file")
+	testCmd.Flags().StringVar(&jsonPath, "json", "",
+		"write results as JSON to this file")
+	rootCmd.AddCommand(testCmd)
+}
+
+// testFileResult is the result of running a test file
+type testFileResult struct {
+	Path   string `json:"path"`
+	Passed bool   `json:"passed"`
+	// Error is the error the file raised, if any
+	Error    string         `json:"error,omitempty"`
+	Duration float64        `json:"duration"`
+	Cases    []xin.TestCase `json:"cases"`
+}
+
+func (r testFileResult) failures() int {
+	n := 0
+	for _, tc := range r.Cases {
+		if !tc.Passed {
+			n++
+		}
+	}
+	return n
+}
+
+// testFiles returns the test files under a path, or the path itself
+// if it is a file.
+func testFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	paths := []string{}
+	err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.HasSuffix(p, "_test.xin") {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// runTestFile runs a test file in a new VM, and reports the file as
+// passed or failed with its results, and its coverage if recorded.
+func runTestFile(path string) (testFileResult, *xin.Coverage) {
+	result := testFileResult{
+		Path:  path,
+		Cases: []xin.TestCase{},
+	}
+
+	start := time.Now()
+	vm, err := newVm()
+	if err == nil {
+		err = vm.Exec(path)
+		// a test file may end itself early with (os::exit 0)
+		if exit, ok := err.(xin.ExitError); ok && exit.Code == 0 {
+			err = nil
+		}
+	}
+	result.Duration = time.Since(start).Seconds()
+
+	if err != nil {
+		result.Error = xin.FormatError(err)
+	} else if asyncErrs := vm.AsyncErrors(); len(asyncErrs) > 0 {
+		result.Error = xin.FormatError(asyncErrs[0])
+	}
+	var coverage *xin.Coverage
+	if vm != nil {
+		result.Cases = vm.TestCases()
+		coverage = vm.StopCoverage()
+	}
+	result.Passed = result.Error == "" && result.failures() == 0
+
+	status := "ok"
+	if !result.Passed {
+		status = "FAIL"
+	}
+	fmt.Printf("%s\t%s\t%d cases\t%.3fs\n", status, path, len(result.Cases), result.Duration)
+	for _, tc := range result.Cases {
+		if !tc.Passed {
+			fmt.Printf("\t- %s: %s\n\t  %s\n", tc.Scope, tc.Name, tc.Message)
+		}
+	}
+	if result.Error != "" {
+		fmt.Printf("\tError: %s\n", result.Error)
+	}
+
+	return result, coverage
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+}
+
+// writeJUnit writes test results to path in the JUnit XML format. Each
+// file is a test suite, and errors raised by a file are reported as
+// an error in an extra test case named after the file.
+func writeJUnit(path string, results []testFileResult) error {
+	report := junitTestSuites{
+		Suites: []junitTestSuite{},
+	}
+	for _, result := range results {
+		suite := junitTestSuite{
+			Name:     result.Path,
+			Tests:    len(result.Cases),
+			Failures: result.failures(),
+			Time:     fmt.Sprintf("%.3f", result.Duration),
+			Cases:    []junitTestCase{},
+		}
+		for _, tc := range result.Cases {
+			jtc := junitTestCase{
+				Name:      tc.Name,
+				ClassName: tc.Scope,
+			}
+			if !tc.Passed {
+				jtc.Failure = &junitProblem{
+					Message: tc.Message,
+				}
+			}
+			suite.Cases = append(suite.Cases, jtc)
+		}
+		if result.Error != "" {
+			suite.Tests++
+			suite.Errors++
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      result.Path,
+				ClassName: result.Path,
+				Error: &junitProblem{
+					Message: result.Error,
+				},
+			})
+		}
+
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Suites = append(report.Suites, suite)
+	}
+
+	out, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append([]byte(xml.Header), append(out, '\n')...), 0644)
+}
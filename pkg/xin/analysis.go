@@ -0,0 +1,563 @@
+package xin
+
+import (
+	"fmt"
+	"io/ioutil"
+	osPath "path"
+	"strings"
+
+	"github.com/rakyll/statik/fs"
+)
+
+// bindingKind is how a name is bound in a Xin source file
+type bindingKind int
+
+const (
+	bindValue bindingKind = iota
+	bindForm
+	bindMacro
+	bindParam
+)
+
+// binding is a name bound in a Xin source file, by a bind form,
+// a form or macro definition, or as a parameter of a form.
+type binding struct {
+	name string
+	kind bindingKind
+	// node is the name node where the name is bound, and form the bind
+	// form that binds it, or the definition a parameter belongs to
+	node *astNode
+	form *astNode
+	// global is set for names bound in the top level frame of the file,
+	// which importing files can see
+	global bool
+	// source is the file the name is bound in
+	source *sourceAnalysis
+	// refs counts the references to the binding in its own file
+	refs int
+}
+
+// signature returns the form signature of a definition,
+// like (vec::map v f), calling the form by name.
+func (b *binding) signature(name string) string {
+	if b.kind != bindForm && b.kind != bindMacro {
+		return name
+	}
+
+	specimen := b.form.leaves[1]
+	parts := make([]string, len(specimen.leaves))
+	parts[0] = name
+	for i, param := range specimen.leaves[1:] {
+		parts[i+1] = param.String()
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// reference is a use of a name in a Xin source file. Its binding is nil
+// if the name is bound by the Vm, like the standard library, or is
+// undefined.
+type reference struct {
+	node    *astNode
+	binding *binding
+}
+
+// call is an invocation of a form by name in a Xin source file. Its
+// binding is nil if the name is not bound in the file or imported.
+type call struct {
+	node    *astNode
+	binding *binding
+}
+
+// sourceImport is an import form in a Xin source file
+type sourceImport struct {
+	node  *astNode
+	alias string
+	// path is the path of the imported file, resolved the way
+	// evalImportForm does, or empty if it is not a string literal
+	path string
+	// source is the analysis of the imported file,
+	// or nil if it could not be read
+	source *sourceAnalysis
+}
+
+// sourceAnalysis is the result of analyzing a Xin source file statically,
+// without evaluating it, for tools like the language server. Names are
+// resolved like resolveNames does, with the names bound at the top level
+// of the file and by its imports visible throughout the file.
+type sourceAnalysis struct {
+	path   string
+	source string
+	lines  []string
+	// err is the error from lexing or parsing the file, if any
+	err  InterpreterError
+	root *astNode
+
+	bindings   []*binding
+	references []reference
+	calls      []call
+	imports    []sourceImport
+	// imported holds the names imported into the file,
+	// with their bindings in the imported files
+	imported map[string]*binding
+	// undefined holds the names that are neither
+	// bound in the file nor by the Vm
+	undefined []*astNode
+	// invalidIfs holds the if forms without exactly three
+	// branches, which cannot be evaluated
+	invalidIfs []*astNode
+}
+
+// stdlibEntry is a native form, or a name bound by the standard library
+type stdlibEntry struct {
+	name string
+	// binding is the binding of the name in its library file,
+	// or nil for native forms
+	binding *binding
+}
+
+// loadStdlibNames returns the names bound in a new Vm, for
+// analyzeSource, and those of them that are native forms or bound in
+// the standard library, with their bindings in the standard library.
+func loadStdlibNames() (map[string]bool, map[string]stdlibEntry, error) {
+	vm, ierr := NewVmWithOptions(VmOptions{
+		Stdin:  strings.NewReader(""),
+		Stdout: ioutil.Discard,
+		Stderr: ioutil.Discard,
+	})
+	if ierr != nil {
+		return nil, nil, fmt.Errorf("could not create Vm: %s", FormatError(ierr))
+	}
+
+	globals := make(map[string]bool)
+	stdlib := make(map[string]stdlibEntry)
+
+	vm.Lock()
+	for f := vm.Frame; f != nil; f = f.Parent {
+		for name := range f.Scope {
+			globals[name] = true
+		}
+	}
+	for name := range vm.evalers {
+		stdlib[name] = stdlibEntry{
+			name: name,
+		}
+	}
+	vm.Unlock()
+
+	statikFs, err := fs.New()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, path := range stdlibFiles {
+		libFile, err := statikFs.Open("/" + path + ".xin")
+		if err != nil {
+			return nil, nil, err
+		}
+		src, err := ioutil.ReadAll(libFile)
+		libFile.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		alias := stdlibAlias(path)
+		lib := analyzeSource(stdlibPath(path), string(src), globals)
+		for _, b := range lib.bindings {
+			if !b.global {
+				continue
+			}
+
+			name := b.name
+			if alias != "" {
+				name = alias + "::" + name
+			}
+			stdlib[name] = stdlibEntry{
+				name:    name,
+				binding: b,
+			}
+		}
+	}
+
+	return globals, stdlib, nil
+}
+
+// analysisScope is a lexical scope of a source file
+type analysisScope struct {
+	parent *analysisScope
+	names  map[string]*binding
+}
+
+func newAnalysisScope(parent *analysisScope) *analysisScope {
+	return &analysisScope{
+		parent: parent,
+		names:  make(map[string]*binding),
+	}
+}
+
+func (sc *analysisScope) lookup(name string) *binding {
+	for s := sc; s != nil; s = s.parent {
+		if b, prs := s.names[name]; prs {
+			return b
+		}
+	}
+	return nil
+}
+
+type analyzer struct {
+	globals map[string]bool
+	// analyses caches the files analyzed for one top level analysis,
+	// so that each file is analyzed once, and import cycles end
+	analyses map[string]*sourceAnalysis
+}
+
+// analyzeSource analyzes a Xin source file at path. globals holds the
+// names bound by the Vm, like builtins and the standard library.
+func analyzeSource(path string, source string, globals map[string]bool) *sourceAnalysis {
+	a := &analyzer{
+		globals:  globals,
+		analyses: make(map[string]*sourceAnalysis),
+	}
+	return a.analyze(path, source)
+}
+
+func (a *analyzer) analyze(path string, source string) *sourceAnalysis {
+	sa := &sourceAnalysis{
+		path:     path,
+		source:   source,
+		lines:    strings.Split(source, "\n"),
+		imported: make(map[string]*binding),
+	}
+	a.analyses[path] = sa
+
+	toks, err := lex(path, strings.NewReader(source))
+	if err != nil {
+		sa.err = err
+		return sa
+	}
+	rootNode, err := parse(toks)
+	if err != nil {
+		sa.err = err
+		return sa
+	}
+	sa.root = &rootNode
+
+	top := newAnalysisScope(nil)
+	sa.declare(sa.root, top, true)
+	a.collectImports(sa, sa.root)
+	sa.walk(sa.root, top, a)
+
+	return sa
+}
+
+// collectImports analyzes the files imported anywhere in the file,
+// and brings the names bound in their top level into the file.
+func (a *analyzer) collectImports(sa *sourceAnalysis, node *astNode) {
+	if !node.isForm || len(node.leaves) == 0 {
+		return
+	}
+
+	head := node.leaves[0]
+	if head.isForm || head.token.kind != tkImportForm || len(node.leaves) < 2 {
+		for _, leaf := range node.leaves {
+			a.collectImports(sa, leaf)
+		}
+		return
+	}
+
+	imp := sourceImport{
+		node: node,
+	}
+	if len(node.leaves) > 2 {
+		aliasNode := node.leaves[2]
+		if !aliasNode.isForm && aliasNode.token.kind == tkName {
+			imp.alias = aliasNode.token.value
+		}
+	}
+
+	pathNode := node.leaves[1]
+	if !pathNode.isForm && pathNode.token.kind == tkStringLiteral {
+		imp.path = osPath.Join(osPath.Dir(sa.path), pathNode.token.value+".xin")
+		imp.source = a.analyses[imp.path]
+		if imp.source == nil {
+			if src, err := ioutil.ReadFile(imp.path); err == nil {
+				imp.source = a.analyze(imp.path, string(src))
+			}
+		}
+	}
+
+	if imp.source != nil {
+		for name, b := range imp.source.exports() {
+			if imp.alias != "" {
+				name = imp.alias + "::" + name
+			}
+			if _, prs := sa.imported[name]; !prs {
+				sa.imported[name] = b
+			}
+		}
+	}
+	sa.imports = append(sa.imports, imp)
+}
+
+// exports returns the names a file binds in its top level frame,
+// which files that import it can see.
+func (sa *sourceAnalysis) exports() map[string]*binding {
+	names := make(map[string]*binding)
+	for name, b := range sa.imported {
+		names[name] = b
+	}
+	for _, b := range sa.bindings {
+		if b.global {
+			names[b.name] = b
+		}
+	}
+	return names
+}
+
+// declare declares every name bound within node in the scope sc,
+// without descending into the bodies of form definitions, like
+// declareNames.
+func (sa *sourceAnalysis) declare(node *astNode, sc *analysisScope, global bool) {
+	if !node.isForm || len(node.leaves) == 0 {
+		return
+	}
+
+	if isDefinition(node) {
+		nameNode := node.leaves[1].leaves[0]
+		if !nameNode.isForm && nameNode.token.kind == tkName {
+			kind := bindForm
+			if node.leaves[0].token.kind == tkMacroForm {
+				kind = bindMacro
+			}
+			sa.bind(sc, nameNode, node, kind, global)
+		}
+		return
+	}
+
+	if isBind(node) {
+		specimen := node.leaves[1]
+		if !specimen.isForm && specimen.token.kind == tkName {
+			sa.bind(sc, specimen, node, bindValue, global)
+		}
+	}
+
+	for _, leaf := range node.leaves {
+		sa.declare(leaf, sc, global)
+	}
+}
+
+func (sa *sourceAnalysis) bind(sc *analysisScope, nameNode *astNode, form *astNode, kind bindingKind, global bool) {
+	name := nameNode.token.value
+	// a name bound more than once in a scope refers to the same
+	// slot, which is found at its first binding
+	if _, prs := sc.names[name]; prs {
+		return
+	}
+
+	b := &binding{
+		name:   name,
+		kind:   kind,
+		node:   nameNode,
+		form:   form,
+		global: global,
+		source: sa,
+	}
+	sc.names[name] = b
+	sa.bindings = append(sa.bindings, b)
+}
+
+// walk resolves the names referenced within node in the scope sc
+func (sa *sourceAnalysis) walk(node *astNode, sc *analysisScope, a *analyzer) {
+	if !node.isForm {
+		if node.token.kind == tkName {
+			sa.resolve(node, sc, a)
+		}
+		return
+	}
+	if len(node.leaves) == 0 {
+		return
+	}
+
+	head := node.leaves[0]
+	if !head.isForm {
+		switch head.token.kind {
+		case tkBindForm, tkMacroForm:
+			if len(node.leaves) != 3 {
+				break
+			}
+
+			specimen, body := node.leaves[1], node.leaves[2]
+			if !specimen.isForm {
+				sa.walk(body, sc, a)
+				return
+			}
+			if len(specimen.leaves) == 0 {
+				break
+			}
+
+			inner := newAnalysisScope(sc)
+			defaults := []*astNode{}
+			for _, param := range specimen.leaves[1:] {
+				if name, ok := restParamName(param); ok {
+					sa.bind(inner, &astNode{
+						token: token{
+							kind:     tkName,
+							value:    name,
+							position: param.position,
+						},
+						position: param.position,
+					}, node, bindParam, false)
+				} else if nameNode, def, ok := optionalParam(param); ok {
+					sa.bind(inner, nameNode, node, bindParam, false)
+					defaults = append(defaults, def)
+				} else if !param.isForm && param.token.kind == tkName {
+					sa.bind(inner, param, node, bindParam, false)
+				}
+			}
+			for _, def := range defaults {
+				sa.declare(def, inner, false)
+			}
+			sa.declare(body, inner, false)
+
+			for _, def := range defaults {
+				sa.walk(def, inner, a)
+			}
+			sa.walk(body, inner, a)
+			return
+		case tkImportForm:
+			// the alias of an import is not a reference
+			if len(node.leaves) > 1 {
+				sa.walk(node.leaves[1], sc, a)
+			}
+			return
+		case tkIfForm:
+			if len(node.leaves) != 4 {
+				sa.invalidIfs = append(sa.invalidIfs, node)
+			}
+		case tkName:
+			b := sa.resolve(head, sc, a)
+			sa.calls = append(sa.calls, call{
+				node:    node,
+				binding: b,
+			})
+
+			// the arguments of a macro are syntax,
+			// which is not evaluated as written
+			if b != nil && b.kind == bindMacro {
+				for _, leaf := range node.leaves[1:] {
+					sa.touch(leaf, sc)
+				}
+				return
+			}
+			for _, leaf := range node.leaves[1:] {
+				sa.walk(leaf, sc, a)
+			}
+			return
+		}
+	}
+
+	for _, leaf := range node.leaves {
+		sa.walk(leaf, sc, a)
+	}
+}
+
+// touch counts the names within the arguments of a macro as uses of
+// the bindings they would resolve to, since macros often evaluate the
+// syntax they are given, without reporting any names as undefined.
+func (sa *sourceAnalysis) touch(node *astNode, sc *analysisScope) {
+	if !node.isForm {
+		if node.token.kind == tkName {
+			if b := sc.lookup(node.token.value); b != nil {
+				b.refs++
+			}
+		}
+		return
+	}
+
+	for _, leaf := range node.leaves {
+		sa.touch(leaf, sc)
+	}
+}
+
+// resolve records a reference to a name, and returns its binding if
+// it is bound in the file or imported into it.
+func (sa *sourceAnalysis) resolve(node *astNode, sc *analysisScope, a *analyzer) *binding {
+	name := node.token.value
+
+	b := sc.lookup(name)
+	if b != nil {
+		b.refs++
+	} else if imported, prs := sa.imported[name]; prs {
+		b = imported
+	} else if !a.globals[name] {
+		sa.undefined = append(sa.undefined, node)
+	}
+
+	sa.references = append(sa.references, reference{
+		node:    node,
+		binding: b,
+	})
+	return b
+}
+
+// sourceOffset finds the line and byte column in the source of a
+// position reported by the lexer, both counted from 0. The lexer
+// reports the newline ending a line as the first column of the next
+// line, so columns of later lines are one past those of the first.
+func (sa *sourceAnalysis) sourceOffset(pos position) (int, int) {
+	line, col := pos.line-1, pos.col-1
+	if line > 0 {
+		col--
+	}
+	if col < 0 && line > 0 {
+		line--
+		col = len(sa.lines[line])
+	}
+	if line < 0 {
+		line = 0
+	}
+	if line >= len(sa.lines) {
+		line = len(sa.lines) - 1
+	}
+	if col > len(sa.lines[line]) {
+		col = len(sa.lines[line])
+	}
+	return line, col
+}
+
+// nameOffset finds the line and the byte columns of the start and end
+// of a name in the source. Names are positioned by the lexer at the
+// character following them.
+func (sa *sourceAnalysis) nameOffset(node *astNode) (int, int, int) {
+	name := node.token.value
+	line, end := sa.sourceOffset(node.position)
+	text := sa.lines[line]
+
+	// a name at the end of the source is positioned
+	// at its last character instead
+	if !strings.HasSuffix(text[:end], name) && end < len(text) &&
+		strings.HasSuffix(text[:end+1], name) {
+		end++
+	}
+	start := end - len(name)
+	if start < 0 {
+		start = 0
+	}
+
+	return line, start, end
+}
+
+// comment returns the comment lines directly above a line
+// of the source, without their comment markers.
+func (sa *sourceAnalysis) comment(line int) string {
+	lines := sa.lines
+
+	start := line - 1
+	for start > 0 && strings.HasPrefix(strings.TrimSpace(lines[start-1]), ";") {
+		start--
+	}
+
+	comment := make([]string, 0, line-1-start)
+	for _, l := range lines[start : line-1] {
+		comment = append(comment, strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(l), ";")))
+	}
+	return strings.Join(comment, "\n")
+}
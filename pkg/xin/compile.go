@@ -0,0 +1,303 @@
+package xin
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// Backend selects how a Vm evaluates Xin programs.
+type Backend int
+
+const (
+	// TreeWalkBackend evaluates the syntax tree directly.
+	TreeWalkBackend Backend = iota
+	// BytecodeBackend compiles each form to bytecode
+	// the first time it is evaluated, and runs the bytecode
+	// on a stack-based executor.
+	BytecodeBackend
+)
+
+func (b Backend) String() string {
+	switch b {
+	case TreeWalkBackend:
+		return "tree"
+	case BytecodeBackend:
+		return "bytecode"
+	default:
+		return "unknown backend"
+	}
+}
+
+type opcode uint8
+
+const (
+	// check for interrupts at a form boundary
+	opCheck opcode = iota
+	// push a constant
+	opConst
+	// push the value of a name
+	opName
+	// bind the value on top of the stack to a name, leaving it there
+	opBind
+	// discard the value on top of the stack
+	opPop
+	// resolve a lazy value on top of the stack
+	opUnlazy
+	// jump if the condition on top of the stack is false,
+	// consuming the condition
+	opJumpIfFalse
+	opJump
+	// if the form on top of the stack is a macro, expand and
+	// evaluate it, then jump past the invocation
+	opMacro
+	// invoke the form below arg arguments on the stack
+	opCall
+	// evaluate a node with the tree-walking evaluator, used for
+	// forms that gain nothing from compilation, like definitions
+	opTree
+)
+
+type instruction struct {
+	op   opcode
+	arg  int
+	node *astNode
+}
+
+// chunk is the compiled bytecode for a single form
+type chunk struct {
+	code   []instruction
+	consts []Value
+}
+
+type compiler struct {
+	chunk
+}
+
+func (c *compiler) emit(op opcode, arg int, node *astNode) int {
+	c.code = append(c.code, instruction{
+		op:   op,
+		arg:  arg,
+		node: node,
+	})
+	return len(c.code) - 1
+}
+
+// patch points the jump instruction at index to the next instruction
+func (c *compiler) patch(index int) {
+	c.code[index].arg = len(c.code)
+}
+
+func compile(node *astNode) *chunk {
+	c := compiler{}
+	c.compileNode(node)
+	return &c.chunk
+}
+
+// compileNode emits code that leaves the result of eval(fr, node) on
+// the stack. Like eval, this result may be lazy, if node is a form
+// invocation in tail position.
+func (c *compiler) compileNode(node *astNode) {
+	if !node.isForm {
+		switch node.token.kind {
+		case tkName:
+			c.emit(opName, 0, node)
+		case tkNumberLiteralInt, tkNumberLiteralHex:
+			c.compileConst(node.token.intv, node)
+		case tkNumberLiteralDecimal:
+			c.compileConst(node.token.fracv, node)
+		case tkStringLiteral:
+			c.compileConst(StringValue(node.token.value), node)
+		case tkValueLiteral:
+			c.compileConst(node.token.literal, node)
+		default:
+			c.emit(opTree, 0, node)
+		}
+		return
+	}
+
+	c.emit(opCheck, 0, node)
+
+	formNode := node.leaves[0]
+	args := node.leaves[1:]
+
+	switch formNode.token.kind {
+	case tkBindForm:
+		if len(args) == 2 && !args[0].isForm && args[0].token.kind == tkName {
+			c.compileStrict(args[1])
+			c.emit(opBind, 0, args[0])
+			return
+		}
+		c.emit(opTree, 0, node)
+	case tkIfForm:
+		if len(args) != 3 {
+			c.emit(opTree, 0, node)
+			return
+		}
+
+		c.compileStrict(args[0])
+		jumpToFalse := c.emit(opJumpIfFalse, 0, args[0])
+		c.compileNode(args[1])
+		jumpToEnd := c.emit(opJump, 0, node)
+		c.patch(jumpToFalse)
+		c.compileNode(args[2])
+		c.patch(jumpToEnd)
+	case tkDoForm:
+		if len(args) == 0 {
+			c.compileConst(zeroValue, nil)
+			return
+		}
+
+		lastIndex := len(args) - 1
+		for _, n := range args[:lastIndex] {
+			c.compileStrict(n)
+			c.emit(opPop, 0, n)
+		}
+		c.compileNode(args[lastIndex])
+	case tkMacroForm, tkImportForm:
+		c.emit(opTree, 0, node)
+	default:
+		if formNode.isForm {
+			c.compileStrict(formNode)
+		} else {
+			c.compileNode(formNode)
+		}
+
+		jumpPastCall := c.emit(opMacro, 0, node)
+		for _, n := range args {
+			c.compileStrict(n)
+		}
+		c.emit(opCall, len(args), node)
+		c.patch(jumpPastCall)
+	}
+}
+
+// compileStrict emits code that leaves the result
+// of unlazyEval(fr, node) on the stack.
+func (c *compiler) compileStrict(node *astNode) {
+	c.compileNode(node)
+	if node.isForm {
+		c.emit(opUnlazy, 0, node)
+	}
+}
+
+// compileConst emits code that pushes v. node is the literal v was
+// compiled from, if any, which is only used to record coverage.
+func (c *compiler) compileConst(v Value, node *astNode) {
+	c.consts = append(c.consts, v)
+	c.emit(opConst, len(c.consts)-1, node)
+}
+
+// execNode is the bytecode backend's counterpart to eval
+func execNode(fr *Frame, node *astNode) (Value, InterpreterError) {
+	if !node.isForm {
+		if cov := fr.Vm.coverage; cov != nil {
+			cov.hit(node)
+		}
+		return evalAtom(fr, node)
+	}
+
+	c := (*chunk)(atomic.LoadPointer(&node.chunk))
+	if c == nil {
+		// if tasks race to compile the same form, each
+		// compiles an equivalent chunk, so either can win
+		c = compile(node)
+		atomic.StorePointer(&node.chunk, unsafe.Pointer(c))
+	}
+
+	return c.exec(fr)
+}
+
+func (c *chunk) exec(fr *Frame) (Value, InterpreterError) {
+	// most forms need only a shallow stack, which can then live
+	// on the Go stack rather than being allocated per evaluation
+	var buffer [8]Value
+	stack := buffer[:0]
+
+	pc := 0
+	for pc < len(c.code) {
+		inst := c.code[pc]
+		pc++
+
+		switch inst.op {
+		case opCheck:
+			if atomic.LoadInt32(fr.Vm.interrupted) != 0 {
+				return nil, InterruptedError{
+					position: inst.node.position,
+				}
+			}
+			if cov := fr.Vm.coverage; cov != nil {
+				cov.hit(inst.node)
+			}
+		case opConst:
+			if cov := fr.Vm.coverage; cov != nil && inst.node != nil {
+				cov.hit(inst.node)
+			}
+			stack = append(stack, c.consts[inst.arg])
+		case opName:
+			if cov := fr.Vm.coverage; cov != nil {
+				cov.hit(inst.node)
+			}
+			val, err := fr.getName(inst.node)
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, val)
+		case opBind:
+			fr.putName(inst.node, stack[len(stack)-1])
+		case opPop:
+			stack = stack[:len(stack)-1]
+		case opUnlazy:
+			val, err := unlazy(stack[len(stack)-1])
+			if err != nil {
+				return nil, err
+			}
+			stack[len(stack)-1] = val
+		case opJumpIfFalse:
+			cond := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if cov := fr.Vm.coverage; cov != nil {
+				cov.branch(inst.node, cond)
+			}
+			switch cond {
+			case trueValue:
+			case falseValue:
+				pc = inst.arg
+			default:
+				return nil, InvalidIfConditionError{
+					cond:     cond,
+					position: inst.node.position,
+				}
+			}
+		case opJump:
+			pc = inst.arg
+		case opMacro:
+			if macro, ok := stack[len(stack)-1].(MacroFormValue); ok {
+				val, err := evalMacroForm(fr, inst.node, macro)
+				if err != nil {
+					return nil, err
+				}
+				stack[len(stack)-1] = val
+				pc = inst.arg
+			}
+		case opCall:
+			base := len(stack) - inst.arg
+			args := make([]Value, inst.arg)
+			copy(args, stack[base:])
+
+			val, err := evalFormWithArgs(fr, stack[base-1], args, inst.node)
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack[:base-1], val)
+		case opTree:
+			val, err := evalTree(fr, inst.node)
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, val)
+		}
+	}
+
+	return stack[len(stack)-1], nil
+}
@@ -0,0 +1,648 @@
+package xin
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rakyll/statik/fs"
+)
+
+// coverageRecorder counts the evaluations of each syntax node, and the
+// branches taken by each if form, keyed by its condition. It is shared
+// by a Vm and the tasks it spawns, which run in parallel, so unlike the
+// profiler it has its own lock.
+type coverageRecorder struct {
+	sync.Mutex
+	nodes    map[*astNode]int64
+	branches map[*astNode]*[2]int64
+}
+
+func newCoverageRecorder() *coverageRecorder {
+	return &coverageRecorder{
+		nodes:    make(map[*astNode]int64),
+		branches: make(map[*astNode]*[2]int64),
+	}
+}
+
+func (cr *coverageRecorder) hit(node *astNode) {
+	cr.Lock()
+	defer cr.Unlock()
+
+	cr.nodes[node]++
+}
+
+// branch records the arm an if form took for the condition cond
+func (cr *coverageRecorder) branch(condNode *astNode, cond Value) {
+	cr.Lock()
+	defer cr.Unlock()
+
+	taken, prs := cr.branches[condNode]
+	if !prs {
+		taken = &[2]int64{}
+		cr.branches[condNode] = taken
+	}
+	switch cond {
+	case trueValue:
+		taken[0]++
+	case falseValue:
+		taken[1]++
+	}
+}
+
+// StartCoverage starts recording which forms are evaluated in the Vm,
+// and which arms of if forms are taken. Tasks spawned once coverage
+// is started are recorded with the Vm.
+func (vm *Vm) StartCoverage() {
+	vm.Lock()
+	defer vm.Unlock()
+
+	vm.coverage = newCoverageRecorder()
+}
+
+// StopCoverage stops recording coverage, and returns the coverage
+// recorded since it started, or nil if it was not recorded. Files
+// whose source cannot be read again, like standard input, are left
+// out of the coverage.
+func (vm *Vm) StopCoverage() *Coverage {
+	vm.Lock()
+	cr := vm.coverage
+	vm.coverage = nil
+	vm.Unlock()
+
+	if cr == nil {
+		return nil
+	}
+
+	cr.Lock()
+	defer cr.Unlock()
+
+	// macros evaluate copies of syntax trees, and imported files may
+	// be parsed more than once, so nodes are matched to the source
+	// by where the lexer positioned them
+	nodes := make(map[lexedNode]int64)
+	branches := make(map[lexedNode][2]int64)
+	paths := make(map[string]bool)
+	for node, count := range cr.nodes {
+		if node.position.line > 0 {
+			nodes[lexedNodeOf(node)] += count
+			paths[node.position.path] = true
+		}
+	}
+	for node, taken := range cr.branches {
+		key := lexedNodeOf(node)
+		sum := branches[key]
+		sum[0] += taken[0]
+		sum[1] += taken[1]
+		branches[key] = sum
+	}
+
+	c := &Coverage{
+		files: make(map[string]*fileCoverage),
+	}
+	for path := range paths {
+		source, err := coverageSource(path)
+		if err != nil {
+			continue
+		}
+		fc, err := newFileCoverage(path, source)
+		if err != nil {
+			continue
+		}
+
+		for key, pos := range fc.lexed {
+			fc.nodes[pos] += nodes[key]
+		}
+		for key, pos := range fc.lexedConds {
+			taken := branches[key]
+			fc.branches[pos] = &taken
+		}
+		fc.lexed = nil
+		fc.lexedConds = nil
+		c.files[path] = fc
+	}
+
+	return c
+}
+
+// lexedNode identifies a syntax node by its path and lexer position,
+// which is the same for every parse of a file
+type lexedNode struct {
+	position
+	isForm bool
+}
+
+func lexedNodeOf(node *astNode) lexedNode {
+	return lexedNode{
+		position: node.position,
+		isForm:   node.isForm,
+	}
+}
+
+// coveragePos is a line and column in a source file, counted from 1
+type coveragePos struct {
+	line int
+	col  int
+}
+
+// Coverage is a record of the forms evaluated in a Vm, and of the
+// arms taken by its if forms, for each source file it evaluated.
+// Coverages can be saved as profiles, merged, and rendered as
+// annotated source.
+type Coverage struct {
+	files map[string]*fileCoverage
+}
+
+// fileCoverage counts the evaluations of each coverable node in a
+// source file, and the arms taken by each if form, by their positions
+// in the source.
+type fileCoverage struct {
+	path     string
+	nodes    map[coveragePos]int64
+	branches map[coveragePos]*[2]int64
+
+	// lexed and lexedConds map the nodes and if conditions of a
+	// parse of the file to their positions in the source, and
+	// are only set while recording coverage
+	lexed      map[lexedNode]coveragePos
+	lexedConds map[lexedNode]coveragePos
+}
+
+// newFileCoverage parses a source file, and finds its coverable nodes,
+// which are those the evaluator evaluates as they are written. Names
+// bound by bind forms, the parameters of definitions, and the heads of
+// forms are not evaluated, and neither are definitions, which are only
+// covered by evaluating their bodies.
+func newFileCoverage(path string, source string) (*fileCoverage, InterpreterError) {
+	toks, err := lex(path, strings.NewReader(source))
+	if err != nil {
+		return nil, err
+	}
+	root, err := parse(toks)
+	if err != nil {
+		return nil, err
+	}
+
+	fc := &fileCoverage{
+		path:       path,
+		nodes:      make(map[coveragePos]int64),
+		branches:   make(map[coveragePos]*[2]int64),
+		lexed:      make(map[lexedNode]coveragePos),
+		lexedConds: make(map[lexedNode]coveragePos),
+	}
+	sa := &sourceAnalysis{
+		lines: strings.Split(source, "\n"),
+	}
+
+	var visit func(node *astNode)
+	visit = func(node *astNode) {
+		pos := sa.coveragePos(node)
+		if !node.isForm {
+			fc.lexed[lexedNodeOf(node)] = pos
+			fc.nodes[pos] = 0
+			return
+		}
+		if len(node.leaves) == 0 {
+			return
+		}
+
+		if !isDefinition(node) {
+			fc.lexed[lexedNodeOf(node)] = pos
+			fc.nodes[pos] = 0
+		}
+
+		head := node.leaves[0]
+		if head.isForm {
+			visit(head)
+		}
+		switch head.token.kind {
+		case tkBindForm, tkMacroForm:
+			if len(node.leaves) == 3 {
+				visit(node.leaves[2])
+			}
+			return
+		case tkImportForm:
+			return
+		case tkIfForm:
+			if len(node.leaves) == 4 {
+				fc.lexedConds[lexedNodeOf(node.leaves[1])] = pos
+				fc.branches[pos] = &[2]int64{}
+			}
+		}
+		for _, leaf := range node.leaves[1:] {
+			visit(leaf)
+		}
+	}
+	// the root of the parse is a do form made by the parser
+	for _, node := range root.leaves[1:] {
+		visit(node)
+	}
+
+	return fc, nil
+}
+
+// coveragePos finds where a node begins in the source
+func (sa *sourceAnalysis) coveragePos(node *astNode) coveragePos {
+	var line, col int
+	if node.isForm || node.token.kind == tkStringLiteral {
+		// string literals are positioned at
+		// their closing quote, by the line they end
+		line, col = sa.sourceOffset(node.position)
+	} else {
+		line, col, _ = sa.nameOffset(node)
+	}
+	return coveragePos{line + 1, col + 1}
+}
+
+// coverageSource reads the source of a file for coverage, from the
+// bundled standard library for paths in the standard library.
+func coverageSource(path string) (string, error) {
+	if strings.HasPrefix(path, stdPathPrefix) {
+		for _, name := range stdlibFiles {
+			if stdlibPath(name) != path {
+				continue
+			}
+
+			statikFs, err := fs.New()
+			if err != nil {
+				return "", err
+			}
+			libFile, err := statikFs.Open("/" + name + ".xin")
+			if err != nil {
+				return "", err
+			}
+			defer libFile.Close()
+
+			src, err := ioutil.ReadAll(libFile)
+			return string(src), err
+		}
+	}
+
+	src, err := ioutil.ReadFile(path)
+	return string(src), err
+}
+
+// Merge adds the counts of another coverage to this one, as when
+// combining the coverage of many programs run in separate Vms.
+func (c *Coverage) Merge(o *Coverage) {
+	for path, ofc := range o.files {
+		fc, prs := c.files[path]
+		if !prs {
+			fc = &fileCoverage{
+				path:     path,
+				nodes:    make(map[coveragePos]int64),
+				branches: make(map[coveragePos]*[2]int64),
+			}
+			c.files[path] = fc
+		}
+
+		for pos, count := range ofc.nodes {
+			fc.nodes[pos] += count
+		}
+		for pos, otaken := range ofc.branches {
+			taken, prs := fc.branches[pos]
+			if !prs {
+				taken = &[2]int64{}
+				fc.branches[pos] = taken
+			}
+			taken[0] += otaken[0]
+			taken[1] += otaken[1]
+		}
+	}
+}
+
+func (c *Coverage) sortedFiles() []*fileCoverage {
+	files := make([]*fileCoverage, 0, len(c.files))
+	for _, fc := range c.files {
+		files = append(files, fc)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].path < files[j].path
+	})
+	return files
+}
+
+func sortedCoveragePos(m map[coveragePos]int64) []coveragePos {
+	ps := make([]coveragePos, 0, len(m))
+	for pos := range m {
+		ps = append(ps, pos)
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i].line == ps[j].line {
+			return ps[i].col < ps[j].col
+		}
+		return ps[i].line < ps[j].line
+	})
+	return ps
+}
+
+const coverageProfileHeader = "mode: count"
+
+// WriteProfile writes the coverage as a text profile, which
+// ReadCoverageProfile reads. After a header line, each line of the
+// profile is a tab-separated record of a coverable node
+//
+//	node <path> <line> <col> <count>
+//
+// or of the arms taken by an if form
+//
+//	branch <path> <line> <col> <true count> <false count>
+func (c *Coverage) WriteProfile(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, coverageProfileHeader)
+	for _, fc := range c.sortedFiles() {
+		for _, pos := range sortedCoveragePos(fc.nodes) {
+			fmt.Fprintf(bw, "node\t%s\t%d\t%d\t%d\n", fc.path, pos.line, pos.col, fc.nodes[pos])
+		}
+
+		branchPositions := make(map[coveragePos]int64, len(fc.branches))
+		for pos := range fc.branches {
+			branchPositions[pos] = 0
+		}
+		for _, pos := range sortedCoveragePos(branchPositions) {
+			taken := fc.branches[pos]
+			fmt.Fprintf(bw, "branch\t%s\t%d\t%d\t%d\t%d\n", fc.path, pos.line, pos.col, taken[0], taken[1])
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadCoverageProfile reads a profile written by WriteProfile
+func ReadCoverageProfile(r io.Reader) (*Coverage, error) {
+	c := &Coverage{
+		files: make(map[string]*fileCoverage),
+	}
+
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() || scanner.Text() != coverageProfileHeader {
+		return nil, fmt.Errorf("not a coverage profile")
+	}
+	for lineNo := 2; scanner.Scan(); lineNo++ {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("invalid coverage profile line %d", lineNo)
+		}
+
+		nums := make([]int64, len(fields)-2)
+		for i, field := range fields[2:] {
+			n, err := strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid coverage profile line %d", lineNo)
+			}
+			nums[i] = n
+		}
+
+		path := fields[1]
+		fc, prs := c.files[path]
+		if !prs {
+			fc = &fileCoverage{
+				path:     path,
+				nodes:    make(map[coveragePos]int64),
+				branches: make(map[coveragePos]*[2]int64),
+			}
+			c.files[path] = fc
+		}
+
+		pos := coveragePos{int(nums[0]), int(nums[1])}
+		switch {
+		case fields[0] == "node" && len(nums) == 3:
+			fc.nodes[pos] += nums[2]
+		case fields[0] == "branch" && len(nums) == 4:
+			fc.branches[pos] = &[2]int64{nums[2], nums[3]}
+		default:
+			return nil, fmt.Errorf("invalid coverage profile line %d", lineNo)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// lineCoverage summarizes the coverage of a line of source
+type lineCoverage struct {
+	// count is the most times a node on the line was evaluated
+	count int64
+	// nodes and covered count the coverable nodes that begin
+	// on the line, and those of them that were evaluated
+	nodes   int
+	covered int
+	// branches holds the arms taken by if forms on the line
+	branches []*[2]int64
+}
+
+func (lc lineCoverage) status() string {
+	switch {
+	case lc.nodes == 0:
+		return ""
+	case lc.covered == 0:
+		return "uncovered"
+	case lc.covered < lc.nodes || lc.partialBranch():
+		return "partial"
+	default:
+		return "covered"
+	}
+}
+
+func (lc lineCoverage) partialBranch() bool {
+	for _, taken := range lc.branches {
+		if taken[0] == 0 || taken[1] == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (fc *fileCoverage) lines() map[int]*lineCoverage {
+	lines := make(map[int]*lineCoverage)
+	line := func(n int) *lineCoverage {
+		lc, prs := lines[n]
+		if !prs {
+			lc = &lineCoverage{}
+			lines[n] = lc
+		}
+		return lc
+	}
+
+	for pos, count := range fc.nodes {
+		lc := line(pos.line)
+		lc.nodes++
+		if count > 0 {
+			lc.covered++
+		}
+		if count > lc.count {
+			lc.count = count
+		}
+	}
+	for _, pos := range sortedBranchPos(fc.branches) {
+		lc := line(pos.line)
+		lc.branches = append(lc.branches, fc.branches[pos])
+	}
+	return lines
+}
+
+func sortedBranchPos(m map[coveragePos]*[2]int64) []coveragePos {
+	ps := make(map[coveragePos]int64, len(m))
+	for pos := range m {
+		ps[pos] = 0
+	}
+	return sortedCoveragePos(ps)
+}
+
+// summary returns the number of coverable lines in the file and of
+// those that were covered, and likewise for the arms of if forms
+func (fc *fileCoverage) summary() (int, int, int, int) {
+	lines, coveredLines := 0, 0
+	for _, lc := range fc.lines() {
+		if lc.nodes > 0 {
+			lines++
+			if lc.covered > 0 {
+				coveredLines++
+			}
+		}
+	}
+
+	arms, coveredArms := 0, 0
+	for _, taken := range fc.branches {
+		arms += 2
+		for _, n := range taken {
+			if n > 0 {
+				coveredArms++
+			}
+		}
+	}
+	return lines, coveredLines, arms, coveredArms
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return 100 * float64(n) / float64(total)
+}
+
+func (fc *fileCoverage) summaryString() string {
+	lines, coveredLines, arms, coveredArms := fc.summary()
+	return fmt.Sprintf("%.1f%% of %d lines, %.1f%% of %d branches",
+		percent(coveredLines, lines), lines, percent(coveredArms, arms), arms)
+}
+
+func branchString(branches []*[2]int64) string {
+	parts := make([]string, len(branches))
+	for i, taken := range branches {
+		parts[i] = fmt.Sprintf("if %d/%d", taken[0], taken[1])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// WriteText writes the source of each file in the coverage, annotated
+// with the number of times each line was evaluated. Lines marked !
+// were never evaluated, and lines marked ~ were partly evaluated, or
+// have if forms that took only one arm. Branch counts are listed as
+// the times each if form took its true and false arms.
+func (c *Coverage) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, fc := range c.sortedFiles() {
+		source, err := coverageSource(fc.path)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(bw, "%s: %s\n", fc.path, fc.summaryString())
+		lines := fc.lines()
+		for i, text := range strings.Split(strings.TrimSuffix(source, "\n"), "\n") {
+			lc, prs := lines[i+1]
+			if !prs || lc.nodes == 0 {
+				fmt.Fprintf(bw, "%8s | %s\n", "", text)
+				continue
+			}
+
+			mark := " "
+			switch lc.status() {
+			case "uncovered":
+				mark = "!"
+			case "partial":
+				mark = "~"
+			}
+			fmt.Fprintf(bw, "%6d %s | %s", lc.count, mark, text)
+			if len(lc.branches) > 0 {
+				fmt.Fprintf(bw, "    [%s]", branchString(lc.branches))
+			}
+			fmt.Fprintln(bw)
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
+
+const coverageHTMLHeader = `<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Xin coverage</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table.summary td { padding: 0 1em 0 0; }
+pre { line-height: 1.4; }
+.line { display: block; }
+.count { display: inline-block; width: 6em; text-align: right; color: #888; margin-right: 1em; }
+.covered { background: #dfd; }
+.partial { background: #ffd; }
+.uncovered { background: #fdd; }
+</style>
+</head>
+<body>
+<h1>Xin coverage</h1>
+`
+
+// WriteHTML writes the coverage as an HTML page, with a summary of
+// each file and its source, highlighting lines that were covered,
+// partly covered, and not covered.
+func (c *Coverage) WriteHTML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	files := c.sortedFiles()
+
+	bw.WriteString(coverageHTMLHeader)
+	bw.WriteString("<table class=\"summary\">\n")
+	for i, fc := range files {
+		fmt.Fprintf(bw, "<tr><td><a href=\"#file%d\">%s</a></td><td>%s</td></tr>\n",
+			i, html.EscapeString(fc.path), fc.summaryString())
+	}
+	bw.WriteString("</table>\n")
+
+	for i, fc := range files {
+		source, err := coverageSource(fc.path)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(bw, "<h2 id=\"file%d\">%s</h2>\n<pre>", i, html.EscapeString(fc.path))
+		lines := fc.lines()
+		for i, text := range strings.Split(strings.TrimSuffix(source, "\n"), "\n") {
+			lc, prs := lines[i+1]
+			if !prs || lc.nodes == 0 {
+				fmt.Fprintf(bw, "<span class=\"line\"><span class=\"count\"></span>%s</span>",
+					html.EscapeString(text))
+				continue
+			}
+
+			title := fmt.Sprintf("%d of %d forms evaluated", lc.covered, lc.nodes)
+			if len(lc.branches) > 0 {
+				title += ", " + branchString(lc.branches)
+			}
+			fmt.Fprintf(bw, "<span class=\"line %s\" title=\"%s\"><span class=\"count\">%d</span>%s</span>",
+				lc.status(), html.EscapeString(title), lc.count, html.EscapeString(text))
+		}
+		bw.WriteString("</pre>\n")
+	}
+
+	bw.WriteString("</body>\n</html>\n")
+	return bw.Flush()
+}
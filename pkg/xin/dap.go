@@ -0,0 +1,592 @@
+package xin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// dapThreadID identifies the only thread in a debug session, which
+// is the evaluation of the program and its async callbacks
+const dapThreadID = 1
+
+type dapRequest struct {
+	Seq       int             `json:"seq"`
+	Type      string          `json:"type"`
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+type dapResponse struct {
+	Seq        int         `json:"seq"`
+	Type       string      `json:"type"`
+	RequestSeq int         `json:"request_seq"`
+	Success    bool        `json:"success"`
+	Command    string      `json:"command"`
+	Message    string      `json:"message,omitempty"`
+	Body       interface{} `json:"body,omitempty"`
+}
+
+type dapEvent struct {
+	Seq   int         `json:"seq"`
+	Type  string      `json:"type"`
+	Event string      `json:"event"`
+	Body  interface{} `json:"body,omitempty"`
+}
+
+type dapSource struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+type dapVariable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+// dapHandle is what a variables reference given
+// to the client refers to, a frame or a value
+type dapHandle struct {
+	frame  *Frame
+	global bool
+	value  Value
+}
+
+// debugSession is a Debug Adapter Protocol session, debugging one
+// Xin program. Requests are handled one at a time, in the order they
+// are read, while the program runs in its own goroutine.
+type debugSession struct {
+	out     io.Writer
+	outLock sync.Mutex
+	seq     int
+
+	vm      *Vm
+	debug   *debugger
+	program string
+	// cancel interrupts the program once it is running,
+	// and exited is closed once it has finished
+	cancel context.CancelFunc
+	exited chan struct{}
+
+	// frames and handles refer to the evaluator's state while
+	// it is paused, and are cleared whenever it resumes
+	frames  []debugFrame
+	handles []dapHandle
+}
+
+// ServeDebugAdapter runs a debug session over the Debug Adapter
+// Protocol, reading requests from r and writing responses and events
+// to w, as a debug adapter launched by an editor does over stdio. The
+// client launches one Xin program, which runs in its own Vm on the
+// tree-walking evaluator, with its standard output sent to the client.
+// ServeDebugAdapter returns once the client disconnects.
+func ServeDebugAdapter(r io.Reader, w io.Writer) error {
+	s := &debugSession{
+		out: w,
+	}
+	defer s.stop()
+
+	reader := textproto.NewReader(bufio.NewReader(r))
+	for {
+		msg, err := readProtocolMessage(reader)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		var req dapRequest
+		if err := json.Unmarshal(msg, &req); err != nil {
+			return fmt.Errorf("invalid message: %s", err)
+		}
+		if req.Type != "request" {
+			continue
+		}
+
+		if done := s.handle(req); done {
+			return nil
+		}
+	}
+}
+
+func (s *debugSession) send(msg interface{}) {
+	s.outLock.Lock()
+	defer s.outLock.Unlock()
+
+	s.seq++
+	switch m := msg.(type) {
+	case *dapResponse:
+		m.Seq = s.seq
+	case *dapEvent:
+		m.Seq = s.seq
+	}
+
+	body, _ := json.Marshal(msg)
+	writeProtocolMessage(s.out, body)
+}
+
+func (s *debugSession) respond(req dapRequest, body interface{}) {
+	s.send(&dapResponse{
+		Type:       "response",
+		RequestSeq: req.Seq,
+		Success:    true,
+		Command:    req.Command,
+		Body:       body,
+	})
+}
+
+func (s *debugSession) fail(req dapRequest, message string) {
+	s.send(&dapResponse{
+		Type:       "response",
+		RequestSeq: req.Seq,
+		Success:    false,
+		Command:    req.Command,
+		Message:    message,
+	})
+}
+
+func (s *debugSession) event(event string, body interface{}) {
+	s.send(&dapEvent{
+		Type:  "event",
+		Event: event,
+		Body:  body,
+	})
+}
+
+// dapOutput sends writes to the standard streams of
+// the program to the client, as output events.
+type dapOutput struct {
+	session  *debugSession
+	category string
+}
+
+func (o dapOutput) Write(p []byte) (int, error) {
+	o.session.event("output", map[string]interface{}{
+		"category": o.category,
+		"output":   string(p),
+	})
+	return len(p), nil
+}
+
+// handle responds to a request, and reports
+// whether the client has ended the session.
+func (s *debugSession) handle(req dapRequest) bool {
+	switch req.Command {
+	case "initialize":
+		s.respond(req, map[string]interface{}{
+			"supportsConfigurationDoneRequest": true,
+			"supportsEvaluateForHovers":        true,
+			"supportsTerminateRequest":         true,
+		})
+		s.event("initialized", nil)
+	case "launch":
+		s.launch(req)
+	case "setBreakpoints":
+		s.setBreakpoints(req)
+	case "setExceptionBreakpoints":
+		s.respond(req, map[string]interface{}{
+			"breakpoints": []interface{}{},
+		})
+	case "configurationDone":
+		if s.vm == nil {
+			s.fail(req, "No program has been launched")
+			return false
+		}
+		if s.cancel != nil {
+			s.fail(req, "The program is already running")
+			return false
+		}
+		s.respond(req, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		s.cancel = cancel
+		go s.run(ctx)
+	case "threads":
+		s.respond(req, map[string]interface{}{
+			"threads": []interface{}{
+				map[string]interface{}{
+					"id":   dapThreadID,
+					"name": "main",
+				},
+			},
+		})
+	case "stackTrace":
+		s.stackTrace(req)
+	case "scopes":
+		s.scopes(req)
+	case "variables":
+		s.variables(req)
+	case "evaluate":
+		s.evaluate(req)
+	case "continue", "next", "stepIn", "stepOut":
+		s.resume(req)
+	case "pause":
+		if s.debug != nil {
+			s.debug.pause("pause")
+		}
+		s.respond(req, nil)
+	case "disconnect", "terminate":
+		s.stop()
+		s.respond(req, nil)
+		return req.Command == "disconnect"
+	default:
+		s.fail(req, fmt.Sprintf("Unsupported request %s", req.Command))
+	}
+
+	return false
+}
+
+func (s *debugSession) launch(req dapRequest) {
+	var args struct {
+		Program     string   `json:"program"`
+		Args        []string `json:"args"`
+		StopOnEntry bool     `json:"stopOnEntry"`
+	}
+	if err := json.Unmarshal(req.Arguments, &args); err != nil || args.Program == "" {
+		s.fail(req, "Launch requires a program to debug")
+		return
+	}
+	if s.vm != nil {
+		s.fail(req, "A program has already been launched")
+		return
+	}
+
+	program, err := filepath.Abs(args.Program)
+	if err != nil {
+		s.fail(req, err.Error())
+		return
+	}
+
+	vm, ierr := NewVmWithOptions(VmOptions{
+		// the standard input of the adapter carries the protocol
+		Stdin:  strings.NewReader(""),
+		Stdout: dapOutput{s, "stdout"},
+		Stderr: dapOutput{s, "stderr"},
+		Args:   append([]string{"xin", program}, args.Args...),
+	})
+	if ierr != nil {
+		s.fail(req, FormatError(ierr))
+		return
+	}
+
+	s.debug = newDebugger(vm, s.stopped)
+	if args.StopOnEntry {
+		s.debug.pause("entry")
+	}
+	vm.debug = s.debug
+	s.vm = vm
+	s.program = program
+	s.exited = make(chan struct{})
+
+	s.respond(req, nil)
+}
+
+// run runs the launched program, and tells the client once it exits
+func (s *debugSession) run(ctx context.Context) {
+	defer close(s.exited)
+
+	exitCode := 0
+	err := s.vm.ExecContext(ctx, s.program)
+	if exit, ok := err.(ExitError); ok {
+		exitCode = exit.Code
+	} else if err != nil {
+		exitCode = 1
+		// the client knows if it interrupted the program
+		if ctx.Err() == nil {
+			dapOutput{s, "stderr"}.Write([]byte(FormatError(err) + "\n"))
+		}
+	}
+
+	s.event("exited", map[string]interface{}{
+		"exitCode": exitCode,
+	})
+	s.event("terminated", nil)
+}
+
+// stop interrupts the program if it is running, and waits for it
+func (s *debugSession) stop() {
+	if s.cancel == nil {
+		return
+	}
+
+	s.cancel()
+	<-s.exited
+}
+
+// stopped is called by the paused evaluator
+func (s *debugSession) stopped(reason string) {
+	s.event("stopped", map[string]interface{}{
+		"reason":            reason,
+		"threadId":          dapThreadID,
+		"allThreadsStopped": true,
+	})
+}
+
+func (s *debugSession) setBreakpoints(req dapRequest) {
+	var args struct {
+		Source      dapSource `json:"source"`
+		Breakpoints []struct {
+			Line int `json:"line"`
+		} `json:"breakpoints"`
+	}
+	if err := json.Unmarshal(req.Arguments, &args); err != nil || args.Source.Path == "" {
+		s.fail(req, "Breakpoints require a source path")
+		return
+	}
+	if s.debug == nil {
+		s.fail(req, "No program has been launched")
+		return
+	}
+
+	path, err := filepath.Abs(args.Source.Path)
+	if err != nil {
+		s.fail(req, err.Error())
+		return
+	}
+
+	lines := make([]int, len(args.Breakpoints))
+	breakpoints := make([]interface{}, len(args.Breakpoints))
+	for i, bp := range args.Breakpoints {
+		lines[i] = bp.Line
+		breakpoints[i] = map[string]interface{}{
+			"verified": true,
+			"line":     bp.Line,
+		}
+	}
+	s.debug.setBreakpoints(path, lines)
+
+	s.respond(req, map[string]interface{}{
+		"breakpoints": breakpoints,
+	})
+}
+
+func (s *debugSession) resume(req dapRequest) {
+	step := stepNone
+	switch req.Command {
+	case "next":
+		step = stepOver
+	case "stepIn":
+		step = stepIn
+	case "stepOut":
+		step = stepOut
+	}
+
+	body := map[string]interface{}{}
+	if req.Command == "continue" {
+		body["allThreadsContinued"] = true
+	}
+	s.respond(req, body)
+
+	if s.debug != nil {
+		s.frames = nil
+		s.handles = nil
+		s.debug.resume(step)
+	}
+}
+
+// inspect runs fn on the paused evaluator, or fails the request
+// if the program is not paused.
+func (s *debugSession) inspect(req dapRequest, fn func()) bool {
+	if s.debug == nil || !s.debug.inspect(fn) {
+		s.fail(req, "The program is not paused")
+		return false
+	}
+	return true
+}
+
+func (s *debugSession) stackTrace(req dapRequest) {
+	var frames []debugFrame
+	if !s.inspect(req, func() {
+		frames = s.debug.stackFrames()
+	}) {
+		return
+	}
+	s.frames = frames
+
+	stackFrames := make([]interface{}, len(frames))
+	for i, f := range frames {
+		stackFrames[i] = map[string]interface{}{
+			"id":     i + 1,
+			"name":   f.name,
+			"line":   f.position.line,
+			"column": f.position.col,
+			"source": dapSource{
+				Name: filepath.Base(f.position.path),
+				Path: f.position.path,
+			},
+		}
+	}
+
+	s.respond(req, map[string]interface{}{
+		"stackFrames": stackFrames,
+		"totalFrames": len(stackFrames),
+	})
+}
+
+// frame returns the frame of a stack frame id given to the client
+func (s *debugSession) frame(id int) (*Frame, bool) {
+	if id < 1 || id > len(s.frames) {
+		return nil, false
+	}
+	return s.frames[id-1].frame, true
+}
+
+// handleFor returns a new variables reference to h
+func (s *debugSession) handleFor(h dapHandle) int {
+	s.handles = append(s.handles, h)
+	return len(s.handles)
+}
+
+func (s *debugSession) scopes(req dapRequest) {
+	var args struct {
+		FrameID int `json:"frameId"`
+	}
+	json.Unmarshal(req.Arguments, &args)
+
+	fr, ok := s.frame(args.FrameID)
+	if !ok {
+		s.fail(req, "Unknown stack frame")
+		return
+	}
+
+	var result []interface{}
+	if !s.inspect(req, func() {
+		for _, sc := range scopes(fr) {
+			result = append(result, map[string]interface{}{
+				"name": sc.name,
+				"variablesReference": s.handleFor(dapHandle{
+					frame:  sc.frame,
+					global: sc.global,
+				}),
+				"expensive": sc.global,
+			})
+		}
+	}) {
+		return
+	}
+
+	s.respond(req, map[string]interface{}{
+		"scopes": result,
+	})
+}
+
+func (s *debugSession) variables(req dapRequest) {
+	var args struct {
+		VariablesReference int `json:"variablesReference"`
+	}
+	json.Unmarshal(req.Arguments, &args)
+
+	ref := args.VariablesReference
+	if ref < 1 || ref > len(s.handles) {
+		s.fail(req, "Unknown variables reference")
+		return
+	}
+	h := s.handles[ref-1]
+
+	result := []dapVariable{}
+	if !s.inspect(req, func() {
+		var vars []debugVariable
+		if h.frame != nil {
+			vars = s.debug.frameVariables(h.frame, h.global)
+		} else {
+			vars = valueVariables(h.value)
+		}
+
+		for _, v := range vars {
+			result = append(result, s.variable(v.name, v.value))
+		}
+	}) {
+		return
+	}
+
+	s.respond(req, map[string]interface{}{
+		"variables": result,
+	})
+}
+
+// variable describes a value to the client. It must be
+// called by the paused evaluator.
+func (s *debugSession) variable(name string, val Value) dapVariable {
+	ref := 0
+	if valueVariables(val) != nil {
+		ref = s.handleFor(dapHandle{
+			value: val,
+		})
+	}
+
+	typ := ""
+	if t, err := typeForm(nil, []Value{val}, nil); err == nil {
+		if form, ok := t.(NativeFormValue); ok {
+			typ = form.name
+		}
+	}
+
+	return dapVariable{
+		Name:               name,
+		Value:              val.Repr(),
+		Type:               typ,
+		VariablesReference: ref,
+	}
+}
+
+// evaluate evaluates an expression in a stack frame of the paused
+// program, for the debug console, watch expressions and hovers.
+func (s *debugSession) evaluate(req dapRequest) {
+	var args struct {
+		Expression string `json:"expression"`
+		FrameID    int    `json:"frameId"`
+	}
+	json.Unmarshal(req.Arguments, &args)
+
+	fr, ok := s.frame(args.FrameID)
+	if !ok {
+		s.fail(req, "Expressions can only be evaluated in a stack frame")
+		return
+	}
+
+	var result dapVariable
+	var err InterpreterError
+	if !s.inspect(req, func() {
+		var val Value
+		val, err = evalDebugExpression(fr, args.Expression)
+		if err == nil {
+			result = s.variable(args.Expression, val)
+		}
+	}) {
+		return
+	}
+	if err != nil {
+		s.fail(req, err.Error())
+		return
+	}
+
+	s.respond(req, map[string]interface{}{
+		"result":             result.Value,
+		"type":               result.Type,
+		"variablesReference": result.VariablesReference,
+	})
+}
+
+func evalDebugExpression(fr *Frame, expr string) (Value, InterpreterError) {
+	toks, err := lex("(debug)", strings.NewReader(expr))
+	if err != nil {
+		return nil, err
+	}
+	rootNode, err := parse(toks)
+	if err != nil {
+		return nil, err
+	}
+	// names are resolved dynamically, since the
+	// lexical scope of the frame is not known here
+	resolveNames(&rootNode, nil)
+
+	return unlazyEval(fr, &rootNode)
+}
@@ -0,0 +1,406 @@
+package xin
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// stdPathPrefix begins the paths of forms in the standard library
+const stdPathPrefix = "(std)"
+
+// stepKind is how far evaluation runs after a debugger resumes it
+type stepKind int
+
+const (
+	// stepNone runs until the next breakpoint or pause
+	stepNone stepKind = iota
+	// stepIn stops at the next form on another line,
+	// including in forms invoked from the current line
+	stepIn
+	// stepOver stops at the next form on another line
+	// in the current invocation or one of its callers
+	stepOver
+	// stepOut stops once the current invocation has returned
+	stepOut
+)
+
+// debugSite identifies a line within one invocation of a form. The
+// debugger does not pause twice at the same site, so that a line with
+// many nested forms is stepped over at once.
+type debugSite struct {
+	path  string
+	line  int
+	stack *stackRecord
+}
+
+// debugCommand is sent by a debug client to a paused evaluator
+type debugCommand struct {
+	// inspect, if set, is run by the paused evaluator, which
+	// then closes done and stays paused. Otherwise, the
+	// evaluator resumes, running until the step is done.
+	inspect func()
+	done    chan struct{}
+	step    stepKind
+}
+
+// debugger pauses evaluation of a Vm at breakpoints, at the end of
+// steps and on request, so that a debug client can inspect its call
+// stack and frames. The evaluator pauses inside hook with the Vm
+// locked, and the client inspects the paused Vm by sending closures
+// for the evaluator to run, so inspection never races with evaluation.
+//
+// Only forms evaluated by the tree-walking evaluator, in the Vm
+// being debugged, are hooked. Spawned tasks are not debugged.
+type debugger struct {
+	// the lock guards the fields set by the client
+	// while the Vm is running
+	sync.Mutex
+	// breakpoints holds breakpoint lines by path
+	breakpoints map[string]map[int]bool
+	// pauseReason, if not empty, pauses evaluation at the
+	// next form, and is reported as the reason for the pause
+	pauseReason string
+	paused      bool
+
+	// stopped is called by the evaluator with the reason
+	// it paused, like "breakpoint" or "step"
+	stopped  func(reason string)
+	commands chan debugCommand
+	// builtins holds the global bindings of the Vm before it runs
+	// the program, which are left out of views of the global frame
+	builtins map[string]Value
+
+	// the remaining fields are only used by the evaluator
+	step      stepKind
+	stepDepth int
+	site      debugSite
+	// frame and node are where evaluation is paused
+	frame *Frame
+	node  *astNode
+}
+
+func newDebugger(vm *Vm, stopped func(string)) *debugger {
+	builtins := make(map[string]Value, len(vm.Frame.Scope))
+	for name, val := range vm.Frame.Scope {
+		builtins[name] = val
+	}
+
+	return &debugger{
+		breakpoints: make(map[string]map[int]bool),
+		stopped:     stopped,
+		commands:    make(chan debugCommand),
+		builtins:    builtins,
+	}
+}
+
+// setBreakpoints replaces the breakpoints in the file at path
+func (d *debugger) setBreakpoints(path string, lines []int) {
+	d.Lock()
+	defer d.Unlock()
+
+	set := make(map[int]bool, len(lines))
+	for _, line := range lines {
+		set[line] = true
+	}
+	d.breakpoints[path] = set
+}
+
+// pause requests the evaluator to pause at the next form
+func (d *debugger) pause(reason string) {
+	d.Lock()
+	defer d.Unlock()
+
+	d.pauseReason = reason
+}
+
+// inspect runs fn on the paused evaluator, and reports
+// whether it did, or the evaluator was not paused.
+func (d *debugger) inspect(fn func()) bool {
+	d.Lock()
+	paused := d.paused
+	d.Unlock()
+	if !paused {
+		return false
+	}
+
+	cmd := debugCommand{
+		inspect: fn,
+		done:    make(chan struct{}),
+	}
+	d.commands <- cmd
+	<-cmd.done
+	return true
+}
+
+// resume resumes the paused evaluator for a step,
+// and reports whether it was paused.
+func (d *debugger) resume(step stepKind) bool {
+	d.Lock()
+	paused := d.paused
+	d.Unlock()
+	if !paused {
+		return false
+	}
+
+	d.commands <- debugCommand{
+		step: step,
+	}
+	return true
+}
+
+// hook is called by the evaluator before evaluating each form,
+// and pauses evaluation if the debugger should stop at the form.
+func (d *debugger) hook(fr *Frame, node *astNode) InterpreterError {
+	vm := fr.Vm
+	pos := node.position
+
+	// the standard library is stepped over, since clients cannot
+	// show its source, and so are forms made by the parser, like
+	// the top level of a program
+	if pos.line == 0 || strings.HasPrefix(pos.path, stdPathPrefix) {
+		return nil
+	}
+
+	site := debugSite{
+		path:  pos.path,
+		line:  pos.line,
+		stack: vm.stack,
+	}
+	if site == d.site {
+		return nil
+	}
+
+	depth := 0
+	if vm.stack != nil {
+		depth = vm.stack.depth
+	}
+
+	reason := ""
+	switch d.step {
+	case stepIn:
+		reason = "step"
+	case stepOver:
+		if depth <= d.stepDepth {
+			reason = "step"
+		}
+	case stepOut:
+		if depth < d.stepDepth {
+			reason = "step"
+		}
+	}
+
+	d.Lock()
+	// forms evaluated while inspecting a paused
+	// evaluator, like watch expressions, never pause
+	if d.paused {
+		d.Unlock()
+		return nil
+	}
+	if d.pauseReason != "" {
+		reason = d.pauseReason
+		d.pauseReason = ""
+	} else if d.breakpoints[pos.path][pos.line] {
+		reason = "breakpoint"
+	}
+	if reason == "" {
+		d.Unlock()
+		return nil
+	}
+	d.paused = true
+	d.Unlock()
+
+	d.site = site
+	d.frame = fr
+	d.node = node
+	d.stopped(reason)
+
+	for {
+		select {
+		case cmd := <-d.commands:
+			if cmd.inspect != nil {
+				cmd.inspect()
+				close(cmd.done)
+				continue
+			}
+
+			d.Lock()
+			d.paused = false
+			d.Unlock()
+
+			d.step = cmd.step
+			d.stepDepth = depth
+			d.frame = nil
+			d.node = nil
+			return nil
+		case <-vm.ctx.Done():
+			d.Lock()
+			d.paused = false
+			d.Unlock()
+
+			return InterruptedError{
+				position: pos,
+			}
+		}
+	}
+}
+
+// debugFrame is an entry in the call stack of a paused evaluator
+type debugFrame struct {
+	name     string
+	position position
+	frame    *Frame
+}
+
+// stackFrames returns the call stack of the paused evaluator, from the
+// form being evaluated to the top level of the program. Each entry is
+// positioned where its form is paused, or where it invoked the next.
+// It must be called by the paused evaluator.
+func (d *debugger) stackFrames() []debugFrame {
+	vm := d.frame.Vm
+
+	frames := []debugFrame{{
+		name:     stackRecordName(vm.stack),
+		position: d.node.position,
+		frame:    d.frame,
+	}}
+	for sr := vm.stack; sr != nil; sr = sr.parent {
+		if sr.node == nil {
+			continue
+		}
+
+		caller := vm.Frame
+		if sr.parent != nil {
+			caller = sr.parent.frame
+		}
+		frames = append(frames, debugFrame{
+			name:     stackRecordName(sr.parent),
+			position: sr.node.position,
+			frame:    caller,
+		})
+	}
+
+	return frames
+}
+
+func stackRecordName(sr *stackRecord) string {
+	switch {
+	case sr == nil:
+		return "(top level)"
+	case sr.name == "":
+		return "(anonymous form)"
+	default:
+		return sr.name
+	}
+}
+
+// debugScope is a frame in the scope chain of a stack frame
+type debugScope struct {
+	name  string
+	frame *Frame
+	// global is set for the frame at the root of the chain
+	global bool
+}
+
+// scopes walks the chain of frames that names in
+// fr are looked up in, from fr to the global frame.
+func scopes(fr *Frame) []debugScope {
+	scs := []debugScope{}
+	for f := fr; f != nil; f = f.Parent {
+		switch {
+		case f.shared:
+			// like builtins in the global frame, the frames
+			// of the standard library are left out
+			continue
+		case f.Parent == nil || f.Parent.shared:
+			scs = append(scs, debugScope{
+				name:   "Globals",
+				frame:  f,
+				global: true,
+			})
+		case f == fr:
+			scs = append(scs, debugScope{
+				name:  "Locals",
+				frame: f,
+			})
+		default:
+			scs = append(scs, debugScope{
+				name:  "Closure",
+				frame: f,
+			})
+		}
+	}
+
+	return scs
+}
+
+// debugVariable is a named value shown by a debug client
+type debugVariable struct {
+	name  string
+	value Value
+}
+
+// frameVariables returns the names bound in a frame, in order. Global
+// frames also hold native forms registered before the program runs,
+// which are left out unless the program has bound the name to another
+// value.
+// It must be called by the paused evaluator.
+func (d *debugger) frameVariables(fr *Frame, global bool) []debugVariable {
+	vars := []debugVariable{}
+	add := func(name string, val Value) {
+		if builtin, prs := d.builtins[name]; global && prs && builtin.Equal(val) {
+			return
+		}
+		vars = append(vars, debugVariable{
+			name:  name,
+			value: val,
+		})
+	}
+
+	if fr.scope != nil {
+		for i, name := range fr.scope.names {
+			if val := fr.slots[i]; val != nil {
+				add(name, val)
+			}
+		}
+	}
+	for name, val := range fr.Scope {
+		add(name, val)
+	}
+
+	sort.Slice(vars, func(i, j int) bool {
+		return vars[i].name < vars[j].name
+	})
+	return vars
+}
+
+// valueVariables returns the items of a composite
+// value, or nil if the value has no items.
+func valueVariables(val Value) []debugVariable {
+	switch v := val.(type) {
+	case VecValue:
+		vars := make([]debugVariable, len(v.underlying.items))
+		for i, item := range v.underlying.items {
+			vars[i] = debugVariable{
+				name:  strconv.Itoa(i),
+				value: item,
+			}
+		}
+		return vars
+	case MapValue:
+		vars := make([]debugVariable, 0, len(*v.items))
+		for k, item := range *v.items {
+			vars = append(vars, debugVariable{
+				name:  k.Repr(),
+				value: item,
+			})
+		}
+		sort.Slice(vars, func(i, j int) bool {
+			return vars[i].name < vars[j].name
+		})
+		return vars
+	}
+
+	return nil
+}
@@ -0,0 +1,458 @@
+package xin
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"runtime"
+)
+
+// NativeFunc is a Go function that can be registered with a Vm
+// and invoked from Xin programs like any other form.
+//
+// A NativeFunc that returns a nil Value returns 0 to Xin. Errors returned
+// from a NativeFunc are raised in the Xin program at the invocation of the
+// form, and may be created with NewRuntimeError, NewArgumentError and
+// NewArgCountError, or be any other Go error.
+type NativeFunc func(fr *Frame, args []Value) (Value, error)
+
+// RegisterForm makes fn available to programs run in the Vm as a native
+// form called name, like "myapp::fetch". Registering a name a second
+// time replaces the previous form. Forms should be registered before
+// evaluating programs that use them.
+func (vm *Vm) RegisterForm(name string, fn NativeFunc) {
+	vm.Lock()
+	defer vm.Unlock()
+
+	evaler := nativeFuncEvaler(fn)
+	vm.evalers[name] = evaler
+	vm.Frame.Put(name, NativeFormValue{
+		name:   name,
+		evaler: evaler,
+	})
+}
+
+// RegisterFunc registers an ordinary Go function as a native form, like
+// RegisterForm, converting its arguments and return values between Go
+// and Xin values. See ToValue for details on how functions are converted.
+func (vm *Vm) RegisterFunc(name string, fn interface{}) error {
+	val, err := ToValue(fn)
+	if err != nil {
+		return err
+	}
+
+	form, ok := val.(NativeFormValue)
+	if !ok {
+		return fmt.Errorf("cannot register %T as a form", fn)
+	}
+
+	vm.Lock()
+	defer vm.Unlock()
+
+	vm.evalers[name] = form.evaler
+	vm.Frame.Put(name, NativeFormValue{
+		name:   name,
+		evaler: form.evaler,
+	})
+	return nil
+}
+
+func nativeFuncEvaler(fn NativeFunc) formEvaler {
+	return func(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+		val, err := fn(fr, args)
+		if err != nil {
+			return nil, hostError(err, node)
+		}
+
+		switch v := val.(type) {
+		case nil:
+			return zeroValue, nil
+		case ErrorValue:
+			if v.position.path == "" {
+				v.position = node.position
+			}
+			return v, nil
+		}
+		return val, nil
+	}
+}
+
+// NewRuntimeError creates an error for a NativeFunc to return
+// when it fails for a reason other than invalid arguments.
+func NewRuntimeError(reason string) InterpreterError {
+	return RuntimeError{
+		reason: reason,
+	}
+}
+
+// NewArgumentError creates an error for a NativeFunc to return
+// when it is given arguments of the wrong types.
+func NewArgumentError(args []Value) InterpreterError {
+	return MismatchedArgumentsError{
+		args: args,
+	}
+}
+
+// NewArgCountError creates an error for a NativeFunc to return
+// when it is given too few arguments.
+func NewArgCountError(required, given int) InterpreterError {
+	return IncorrectNumberOfArgsError{
+		required: required,
+		given:    given,
+	}
+}
+
+// NewErrorValue creates a Xin error value, for a NativeFunc to
+// return when it fails in a way programs are expected to handle.
+func NewErrorValue(message, kind string) ErrorValue {
+	return ErrorValue{
+		message: message,
+		kind:    kind,
+	}
+}
+
+// hostError attributes an error returned by a NativeFunc to
+// the node at which the native form was invoked.
+func hostError(err error, node *astNode) InterpreterError {
+	switch e := err.(type) {
+	case RuntimeError:
+		if e.position.path == "" {
+			e.position = node.position
+		}
+		return e
+	case MismatchedArgumentsError:
+		if e.node == nil {
+			e.node = node
+		}
+		return e
+	case IncorrectNumberOfArgsError:
+		if e.node == nil {
+			e.node = node
+		}
+		return e
+	case raisedError:
+		if e.value.position.path == "" {
+			e.value.position = node.position
+		}
+		return e
+	case InterpreterError:
+		return e
+	default:
+		return RuntimeError{
+			reason:   err.Error(),
+			position: node.position,
+		}
+	}
+}
+
+var valueType = reflect.TypeOf((*Value)(nil)).Elem()
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+var nativeFuncType = reflect.TypeOf(NativeFunc(nil))
+
+// ToValue converts a Go value to a Xin value. Integers become ints,
+// floats become fracs, strings and byte slices become strings, bools
+// become 1 or 0, slices and arrays become vecs, and maps become maps,
+// converting their elements recursively. Unsigned integers too large
+// for an int are an error. A nil converts to 0, and Xin values are
+// returned as-is.
+//
+// Functions become native forms that convert their arguments from Xin
+// values to the types of the function's parameters, and return either
+// the function's single result or, if the function also returns an
+// error, its first result. Arguments that do not fit a parameter, like a
+// frac with a fractional part or a negative int given for an unsigned
+// integer, are an error. A NativeFunc becomes a native form directly.
+// Each converted function is a distinct form, equal only to itself.
+func ToValue(v interface{}) (Value, error) {
+	switch val := v.(type) {
+	case nil:
+		return zeroValue, nil
+	case Value:
+		return val, nil
+	case int:
+		return IntValue(val), nil
+	case int64:
+		return IntValue(val), nil
+	case float64:
+		return FracValue(val), nil
+	case string:
+		return StringValue(val), nil
+	case []byte:
+		return StringValue(append([]byte{}, val...)), nil
+	case bool:
+		if val {
+			return trueValue, nil
+		}
+		return falseValue, nil
+	case NativeFunc:
+		return newNativeForm("native", nativeFuncEvaler(val)), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return IntValue(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := rv.Uint()
+		if n > math.MaxInt64 {
+			return nil, fmt.Errorf("cannot convert %d to a Xin int: overflows int64", n)
+		}
+		return IntValue(n), nil
+	case reflect.Float32, reflect.Float64:
+		return FracValue(rv.Float()), nil
+	case reflect.String:
+		return StringValue(rv.String()), nil
+	case reflect.Bool:
+		return ToValue(rv.Bool())
+	case reflect.Slice, reflect.Array:
+		items := make([]Value, rv.Len())
+		for i := range items {
+			item, err := ToValue(rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return NewVecValue(items), nil
+	case reflect.Map:
+		m := NewMapValue()
+		iter := rv.MapRange()
+		for iter.Next() {
+			key, err := ToValue(iter.Key().Interface())
+			if err != nil {
+				return nil, err
+			}
+			val, err := ToValue(iter.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			m.set(key, val)
+		}
+		return m, nil
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return zeroValue, nil
+		}
+		return ToValue(rv.Elem().Interface())
+	case reflect.Func:
+		return funcToValue(rv)
+	}
+
+	return nil, fmt.Errorf("cannot convert %T to a Xin value", v)
+}
+
+func funcToValue(fn reflect.Value) (Value, error) {
+	t := fn.Type()
+	if t.ConvertibleTo(nativeFuncType) {
+		return newNativeForm("native", nativeFuncEvaler(fn.Convert(nativeFuncType).Interface().(NativeFunc))), nil
+	}
+
+	switch t.NumOut() {
+	case 0, 1:
+	case 2:
+		if t.Out(1) != errorType {
+			return nil, fmt.Errorf("cannot convert %s to a Xin form: second result must be an error", t)
+		}
+	default:
+		return nil, fmt.Errorf("cannot convert %s to a Xin form: too many results", t)
+	}
+
+	name := "native"
+	if f := runtime.FuncForPC(fn.Pointer()); f != nil {
+		name = f.Name()
+	}
+
+	required := t.NumIn()
+	if t.IsVariadic() {
+		required--
+	}
+
+	evaler := func(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+		if len(args) < required {
+			return nil, IncorrectNumberOfArgsError{
+				node:     node,
+				required: required,
+				given:    len(args),
+			}
+		}
+		if !t.IsVariadic() {
+			args = args[:required]
+		}
+
+		in := make([]reflect.Value, len(args))
+		for i, arg := range args {
+			var paramType reflect.Type
+			if t.IsVariadic() && i >= required {
+				paramType = t.In(required).Elem()
+			} else {
+				paramType = t.In(i)
+			}
+
+			param, err := fromValueTo(arg, paramType)
+			if err != nil {
+				return nil, MismatchedArgumentsError{
+					node: node,
+					args: args,
+				}
+			}
+			in[i] = param
+		}
+
+		out := fn.Call(in)
+		if len(out) == 0 {
+			return zeroValue, nil
+		}
+
+		last := out[len(out)-1]
+		if t.Out(len(out)-1) == errorType {
+			if !last.IsNil() {
+				return nil, hostError(last.Interface().(error), node)
+			}
+			if len(out) == 1 {
+				return zeroValue, nil
+			}
+		}
+
+		val, err := ToValue(out[0].Interface())
+		if err != nil {
+			return nil, RuntimeError{
+				reason:   err.Error(),
+				position: node.position,
+			}
+		}
+		return val, nil
+	}
+
+	return newNativeForm(name, evaler), nil
+}
+
+// FromValue converts a Xin value to a Go value. Ints become int64, fracs
+// become float64, strings become string, vecs become []interface{}, and
+// maps become map[string]interface{}, keyed by the string form of each
+// key. Other values, like forms and streams, are returned as-is.
+func FromValue(v Value) interface{} {
+	switch val := v.(type) {
+	case IntValue:
+		return int64(val)
+	case FracValue:
+		return float64(val)
+	case StringValue:
+		return string(val)
+	case VecValue:
+		items := make([]interface{}, len(val.underlying.items))
+		for i, item := range val.underlying.items {
+			items[i] = FromValue(item)
+		}
+		return items
+	case MapValue:
+		m := make(map[string]interface{}, len(*val.items))
+		for k, item := range *val.items {
+			m[k.String()] = FromValue(item)
+		}
+		return m
+	}
+
+	return v
+}
+
+// fromValueTo converts a Xin value to a Go value of type t.
+func fromValueTo(v Value, t reflect.Type) (reflect.Value, error) {
+	if t.Kind() == reflect.Interface && t.Implements(valueType) {
+		if reflect.TypeOf(v).Implements(t) {
+			return reflect.ValueOf(v), nil
+		}
+	} else if reflect.TypeOf(v) == t {
+		return reflect.ValueOf(v), nil
+	}
+
+	mismatch := fmt.Errorf("cannot convert %s to %s", v, t)
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := wholeNumber(v)
+		if ok && !reflect.Zero(t).OverflowInt(n) {
+			return reflect.ValueOf(n).Convert(t), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := wholeNumber(v)
+		if ok && n >= 0 && !reflect.Zero(t).OverflowUint(uint64(n)) {
+			return reflect.ValueOf(n).Convert(t), nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch val := v.(type) {
+		case IntValue:
+			return reflect.ValueOf(float64(val)).Convert(t), nil
+		case FracValue:
+			return reflect.ValueOf(float64(val)).Convert(t), nil
+		}
+	case reflect.String:
+		if val, ok := v.(StringValue); ok {
+			return reflect.ValueOf(string(val)).Convert(t), nil
+		}
+	case reflect.Bool:
+		if val, ok := v.(IntValue); ok {
+			return reflect.ValueOf(val != 0).Convert(t), nil
+		}
+	case reflect.Slice:
+		if val, ok := v.(StringValue); ok && t.Elem().Kind() == reflect.Uint8 {
+			return reflect.ValueOf(append([]byte{}, val...)).Convert(t), nil
+		}
+		if val, ok := v.(VecValue); ok {
+			items := val.underlying.items
+			slice := reflect.MakeSlice(t, len(items), len(items))
+			for i, item := range items {
+				elem, err := fromValueTo(item, t.Elem())
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				slice.Index(i).Set(elem)
+			}
+			return slice, nil
+		}
+	case reflect.Map:
+		if val, ok := v.(MapValue); ok {
+			m := reflect.MakeMapWithSize(t, len(*val.items))
+			for k, item := range *val.items {
+				key, err := fromValueTo(dehashKey(k), t.Key())
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				elem, err := fromValueTo(item, t.Elem())
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				m.SetMapIndex(key, elem)
+			}
+			return m, nil
+		}
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			goVal := FromValue(v)
+			return reflect.ValueOf(&goVal).Elem(), nil
+		}
+	}
+
+	return reflect.Value{}, mismatch
+}
+
+// wholeNumber converts an int, or a frac with no fractional part,
+// to an int64, and reports whether v was such a number.
+func wholeNumber(v Value) (int64, bool) {
+	switch val := v.(type) {
+	case IntValue:
+		return int64(val), true
+	case FracValue:
+		n := int64(val)
+		return n, FracValue(n) == val
+	}
+	return 0, false
+}
+
+// dehashKey recovers the value of a map key that does not need
+// a Vm to be restored, like a string.
+func dehashKey(k Value) Value {
+	if str, ok := k.(hashableStringProxy); ok {
+		return StringValue(str)
+	}
+	return k
+}
@@ -5,8 +5,34 @@ import (
 	"strings"
 )
 
+// maxStackTraceDepth limits how many stack records are printed
+// as part of a formatted error, so deep recursion stays legible.
+const maxStackTraceDepth = 32
+
 func FormatError(e InterpreterError) string {
-	return e.Error() + "\n\t at " + e.pos().String()
+	s := e.Error() + "\n\t at " + e.pos().String()
+
+	traced, ok := e.(stackTraceError)
+	if !ok {
+		return s
+	}
+
+	depth := 0
+	for sr := traced.stack; sr != nil; sr = sr.parent {
+		if depth == maxStackTraceDepth {
+			remaining := 0
+			for ; sr != nil; sr = sr.parent {
+				remaining++
+			}
+			s += fmt.Sprintf("\n\t ... %d more", remaining)
+			break
+		}
+
+		s += "\n\t in " + sr.String()
+		depth++
+	}
+
+	return s
 }
 
 type InterpreterError interface {
@@ -14,6 +40,36 @@ type InterpreterError interface {
 	pos() position
 }
 
+// stackTraceError annotates an InterpreterError with the Xin call stack
+// at the point where the error was raised.
+type stackTraceError struct {
+	InterpreterError
+	stack *stackRecord
+}
+
+// withStackTrace attaches a call stack to an error, unless the error
+// already carries the (deeper) stack from where it was first raised.
+func withStackTrace(e InterpreterError, stack *stackRecord) InterpreterError {
+	if _, ok := e.(stackTraceError); ok {
+		return e
+	}
+
+	return stackTraceError{
+		InterpreterError: e,
+		stack:            stack,
+	}
+}
+
+// unwrapError returns the underlying error of a stack-traced error,
+// for use in type checks against specific error types.
+func unwrapError(e InterpreterError) InterpreterError {
+	if traced, ok := e.(stackTraceError); ok {
+		return traced.InterpreterError
+	}
+
+	return e
+}
+
 type UndefinedNameError struct {
 	name     string
 	position position
@@ -154,15 +210,61 @@ func (e RuntimeError) pos() position {
 	return e.position
 }
 
+type InterruptedError struct {
+	position position
+}
+
+func (e InterruptedError) Error() string {
+	return "Evaluation interrupted"
+}
+
+func (e InterruptedError) pos() position {
+	return e.position
+}
+
+// ExitError ends an evaluation in which the program called os::exit.
+// Code is the exit status the program asked for.
+type ExitError struct {
+	Code     int
+	position position
+}
+
+func (e ExitError) Error() string {
+	return fmt.Sprintf("Program exited with status %d", e.Code)
+}
+
+func (e ExitError) pos() position {
+	return e.position
+}
+
 type IncorrectNumberOfArgsError struct {
-	node     *astNode
+	node *astNode
+	// name of the form invoked, if known
+	name     string
 	required int
 	given    int
+	// for forms with optional or rest parameters,
+	// required is the least number of args accepted
+	atLeast bool
+	// for forms given too many args with optional parameters,
+	// required is the most number of args accepted
+	atMost bool
 }
 
 func (e IncorrectNumberOfArgsError) Error() string {
-	return fmt.Sprintf("Incorrect number of args in %s: requires %d but got %d",
-		e.node, e.required, e.given)
+	bound := ""
+	if e.atLeast {
+		bound = "at least "
+	} else if e.atMost {
+		bound = "at most "
+	}
+
+	if e.name != "" {
+		return fmt.Sprintf("Incorrect number of args to %s in %s: requires %s%d but got %d",
+			e.name, e.node, bound, e.required, e.given)
+	}
+	return fmt.Sprintf("Incorrect number of args in %s: requires %s%d but got %d",
+		e.node, bound, e.required, e.given)
 }
 
 func (e IncorrectNumberOfArgsError) pos() position {
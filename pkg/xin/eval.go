@@ -3,6 +3,7 @@ package xin
 import (
 	"fmt"
 	"strings"
+	"sync/atomic"
 )
 
 // these values are interned
@@ -11,11 +12,23 @@ const trueValue = IntValue(1)
 const falseValue = zeroValue
 
 type Frame struct {
-	Vm     *Vm
+	Vm *Vm
+	// Scope holds names bound dynamically in this frame, like names
+	// bound at the top level of a program, or brought in by imports
 	Scope  map[string]Value
 	Parent *Frame
 
+	// names resolved statically to this frame's lexical scope
+	// are stored in slots, in the order given by scope
+	scope *scopeInfo
+	slots []Value
+
 	cwd *string
+
+	// shared frames, which hold builtins and the standard library,
+	// are never changed once loaded, so they are shared by a Vm and
+	// the Vms of the tasks it spawns rather than copied into them
+	shared bool
 }
 
 func newFrame(parent *Frame) *Frame {
@@ -33,8 +46,31 @@ func newFrame(parent *Frame) *Frame {
 	}
 }
 
+// newScopedFrame creates a frame for evaluating the body
+// of a form definition with the given lexical scope.
+func newScopedFrame(parent *Frame, sc *scopeInfo) *Frame {
+	if sc == nil {
+		return newFrame(parent)
+	}
+
+	return &Frame{
+		Vm:     parent.Vm,
+		Parent: parent,
+		scope:  sc,
+		slots:  make([]Value, len(sc.names)),
+		cwd:    parent.cwd,
+	}
+}
+
 func (fr *Frame) String() string {
-	ss := make([]string, 0, len(fr.Scope))
+	ss := make([]string, 0, len(fr.Scope)+len(fr.slots))
+	if fr.scope != nil {
+		for i, name := range fr.scope.names {
+			if val := fr.slots[i]; val != nil {
+				ss = append(ss, name+"\t"+val.String())
+			}
+		}
+	}
 	for name, val := range fr.Scope {
 		ss = append(ss, name+"\t"+val.String())
 	}
@@ -52,11 +88,23 @@ func (fr *Frame) String() string {
 	)
 }
 
+// local looks up a name bound in this frame only
+func (fr *Frame) local(name string) (Value, bool) {
+	if fr.scope != nil {
+		if slot, prs := fr.scope.slots[name]; prs && fr.slots[slot] != nil {
+			return fr.slots[slot], true
+		}
+	}
+
+	val, prs := fr.Scope[name]
+	return val, prs
+}
+
 func (fr *Frame) Get(name string, pos position) (Value, InterpreterError) {
-	if val, prs := fr.Scope[name]; prs {
-		return val, nil
-	} else if fr.Parent != nil {
-		return fr.Parent.Get(name, pos)
+	for f := fr; f != nil; f = f.Parent {
+		if val, prs := f.local(name); prs {
+			return val, nil
+		}
 	}
 	return nil, UndefinedNameError{
 		name:     name,
@@ -64,16 +112,71 @@ func (fr *Frame) Get(name string, pos position) (Value, InterpreterError) {
 	}
 }
 
+// getName looks up the value of a name node, using its lexical
+// address if it was resolved statically by resolveNames.
+func (fr *Frame) getName(node *astNode) (Value, InterpreterError) {
+	if !node.scoped {
+		return fr.Get(node.token.value, node.position)
+	}
+
+	f := fr
+	for d := 0; d < node.depth; d++ {
+		// intermediate frames may only hold the name
+		// if it was bound dynamically, e.g. by an import
+		if f.Scope != nil {
+			if val, prs := f.Scope[node.token.value]; prs {
+				return val, nil
+			}
+		}
+		f = f.Parent
+	}
+
+	if val := f.slots[node.slot]; val != nil {
+		return val, nil
+	}
+
+	// the name has not been bound in its scope yet,
+	// so it may still refer to a name further up
+	if f.Parent != nil {
+		return f.Parent.Get(node.token.value, node.position)
+	}
+	return nil, UndefinedNameError{
+		name:     node.token.value,
+		position: node.position,
+	}
+}
+
 func (fr *Frame) Put(name string, val Value) {
+	if fr.scope != nil {
+		if slot, prs := fr.scope.slots[name]; prs {
+			fr.slots[slot] = val
+			return
+		}
+	}
+
+	if fr.Scope == nil {
+		fr.Scope = make(map[string]Value)
+	}
 	fr.Scope[name] = val
 }
 
+// putName binds a value to a name node in this frame,
+// using its slot if it was resolved statically.
+func (fr *Frame) putName(node *astNode, val Value) {
+	if node.scoped && node.depth == 0 {
+		fr.slots[node.slot] = val
+		return
+	}
+
+	fr.Put(node.token.value, val)
+}
+
 func (fr *Frame) Up(name string, val Value, pos position) InterpreterError {
-	if _, prs := fr.Scope[name]; prs {
-		fr.Put(name, val)
-		return nil
-	} else if fr.Parent != nil {
-		return fr.Parent.Up(name, val, pos)
+	for f := fr; f != nil; f = f.Parent {
+		if _, prs := f.local(name); prs {
+			f.Put(name, val)
+			return nil
+		}
 	}
 	return UndefinedNameError{
 		name:     name,
@@ -82,6 +185,18 @@ func (fr *Frame) Up(name string, val Value, pos position) InterpreterError {
 }
 
 func eval(fr *Frame, node *astNode) (Value, InterpreterError) {
+	if fr.Vm.backend == BytecodeBackend {
+		return execNode(fr, node)
+	}
+
+	return evalTree(fr, node)
+}
+
+func evalTree(fr *Frame, node *astNode) (Value, InterpreterError) {
+	if cov := fr.Vm.coverage; cov != nil {
+		cov.hit(node)
+	}
+
 	if node.isForm {
 		return evalForm(fr, node)
 	}
@@ -90,11 +205,25 @@ func eval(fr *Frame, node *astNode) (Value, InterpreterError) {
 }
 
 func evalForm(fr *Frame, node *astNode) (Value, InterpreterError) {
+	if atomic.LoadInt32(fr.Vm.interrupted) != 0 {
+		return nil, InterruptedError{
+			position: node.position,
+		}
+	}
+
+	if fr.Vm.debug != nil {
+		if err := fr.Vm.debug.hook(fr, node); err != nil {
+			return nil, err
+		}
+	}
+
 	formNode := node.leaves[0]
 
 	switch formNode.token.kind {
 	case tkBindForm:
 		return evalBindForm(fr, node.leaves[1:])
+	case tkMacroForm:
+		return evalMacroBindForm(fr, node.leaves[1:])
 	case tkIfForm:
 		return evalIfForm(fr, node.leaves[1:])
 	case tkDoForm:
@@ -102,11 +231,15 @@ func evalForm(fr *Frame, node *astNode) (Value, InterpreterError) {
 	case tkImportForm:
 		return evalImportForm(fr, node.leaves[1:])
 	case tkName:
-		maybeForm, err := fr.Get(formNode.token.value, formNode.position)
+		maybeForm, err := fr.getName(formNode)
 		if err != nil {
 			return nil, err
 		}
 
+		if macro, ok := maybeForm.(MacroFormValue); ok {
+			return evalMacroForm(fr, node, macro)
+		}
+
 		return evalFormValue(fr, node, maybeForm)
 	default:
 		maybeForm, err := unlazyEval(fr, formNode)
@@ -114,6 +247,10 @@ func evalForm(fr *Frame, node *astNode) (Value, InterpreterError) {
 			return nil, err
 		}
 
+		if macro, ok := maybeForm.(MacroFormValue); ok {
+			return evalMacroForm(fr, node, macro)
+		}
+
 		return evalFormValue(fr, node, maybeForm)
 	}
 }
@@ -133,25 +270,52 @@ func evalFormValue(fr *Frame, node *astNode, maybeForm Value) (Value, Interprete
 	return evalFormWithArgs(fr, maybeForm, args, node)
 }
 
+// evalFormWithArgs invokes a form with evaluated arguments. Forms
+// invoked from the standard library are invoked leniently, since they
+// are the callbacks given to its higher-order forms.
 func evalFormWithArgs(fr *Frame, maybeForm Value, args []Value, node *astNode) (Value, InterpreterError) {
+	return callFormWithArgs(fr, maybeForm, args, node, strings.HasPrefix(node.path, stdPathPrefix))
+}
+
+func callFormWithArgs(fr *Frame, maybeForm Value, args []Value, node *astNode, lenient bool) (Value, InterpreterError) {
 	switch form := maybeForm.(type) {
 	case FormValue:
-		localFrame := newFrame(form.frame)
-
-		nargs := len(*form.arguments)
-		if len(args) < nargs {
-			nargs = len(args)
-		}
-		for i, v := range args[:nargs] {
-			localFrame.Put((*form.arguments)[i], v)
+		// the form's frame may be shared with other Vms,
+		// but its body is evaluated in the Vm that invoked it
+		localFrame := newScopedFrame(form.frame, form.scope)
+		localFrame.Vm = fr.Vm
+		err := bindArgs(localFrame, form.arguments, form.scope, args, form.name, node, lenient)
+		if err != nil {
+			return nil, err
 		}
 
 		return LazyValue{
-			frame: localFrame,
-			node:  form.definition,
+			frame:    localFrame,
+			node:     form.definition,
+			name:     form.name,
+			callsite: node,
 		}, nil
 	case NativeFormValue:
-		return form.evaler(fr, args, node)
+		vm := fr.Vm
+
+		var val Value
+		var err InterpreterError
+		if vm.profile != nil {
+			n, start := vm.profile.enter(profFunc{name: form.name})
+			val, err = form.evaler(fr, args, node)
+			vm.profile.exit(n, start)
+		} else {
+			val, err = form.evaler(fr, args, node)
+		}
+		if err != nil {
+			return nil, withStackTrace(err, &stackRecord{
+				parent: vm.stack,
+				name:   form.name,
+				node:   node,
+			})
+		}
+
+		return val, nil
 	}
 
 	return nil, InvalidFormError{
@@ -179,17 +343,31 @@ func unlazyEvalFormWithArgs(fr *Frame, maybeForm Value, args []Value, node *astN
 	return unlazy(val)
 }
 
+// unlazyEvalCallback calls a callback given to a native form. Like
+// callbacks given to the standard library, any extra arguments the
+// callback does not take are ignored.
+func unlazyEvalCallback(fr *Frame, callback Value, args []Value, node *astNode) (Value, InterpreterError) {
+	val, err := callFormWithArgs(fr, callback, args, node, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return unlazy(val)
+}
+
 func evalAtom(fr *Frame, node *astNode) (Value, InterpreterError) {
 	tok := node.token
 	switch tok.kind {
 	case tkName:
-		return fr.Get(tok.value, node.position)
+		return fr.getName(node)
 	case tkNumberLiteralInt, tkNumberLiteralHex:
 		return tok.intv, nil
 	case tkNumberLiteralDecimal:
 		return tok.fracv, nil
 	case tkStringLiteral:
 		return StringValue(tok.value), nil
+	case tkValueLiteral:
+		return tok.literal, nil
 	default:
 		panic(fmt.Sprintf("Unrecognized token type: %d", node.token.kind))
 	}
@@ -213,22 +391,26 @@ func evalBindForm(fr *Frame, args []*astNode) (Value, InterpreterError) {
 			formName = formNameNode.token.value
 		}
 
-		args := specimen.leaves[1:]
-		argNames := make(argList, len(args))
-		for i, n := range args {
-			if !n.isForm && n.token.kind == tkName {
-				argNames[i] = n.token.value
-			} else {
-				return nil, InvalidBindError{nodes: args}
+		params, errNode := parseArgList(specimen.leaves[1:])
+		if errNode != nil {
+			return nil, InvalidBindError{
+				nodes:    args,
+				position: errNode.position,
 			}
 		}
 
 		form := FormValue{
 			frame:      fr,
-			arguments:  &argNames,
+			name:       formName,
+			scope:      specimen.scope,
+			arguments:  params,
 			definition: body,
 		}
-		fr.Put(formName, form)
+		if formName == "" {
+			fr.Put(formName, form)
+		} else {
+			fr.putName(formNameNode, form)
+		}
 
 		return form, nil
 	}
@@ -242,10 +424,138 @@ func evalBindForm(fr *Frame, args []*astNode) (Value, InterpreterError) {
 		return nil, err
 	}
 
-	fr.Put(specimen.token.value, val)
+	fr.putName(specimen, val)
 	return val, nil
 }
 
+// restPrefix marks the last parameter of a form as a rest parameter,
+// as in (: (f a ...rest) ...)
+const restPrefix = "..."
+
+func restParamName(node *astNode) (string, bool) {
+	if node.isForm || node.token.kind != tkName {
+		return "", false
+	}
+
+	name := node.token.value
+	if len(name) > len(restPrefix) && strings.HasPrefix(name, restPrefix) {
+		return name[len(restPrefix):], true
+	}
+	return "", false
+}
+
+// optionalParam returns the name and default value expression
+// of an optional parameter, as in (: (f a (b default)) ...)
+func optionalParam(node *astNode) (*astNode, *astNode, bool) {
+	if !node.isForm || len(node.leaves) != 2 {
+		return nil, nil, false
+	}
+
+	nameNode := node.leaves[0]
+	if nameNode.isForm || nameNode.token.kind != tkName {
+		return nil, nil, false
+	}
+
+	return nameNode, node.leaves[1], true
+}
+
+// parseArgList reads the parameters of a form definition. If they are
+// invalid, it returns the offending node.
+func parseArgList(params []*astNode) (*argList, *astNode) {
+	al := argList{
+		names:    make([]string, 0, len(params)),
+		defaults: make([]*astNode, 0, len(params)),
+	}
+
+	for i, n := range params {
+		if rest, ok := restParamName(n); ok {
+			if i != len(params)-1 {
+				return nil, n
+			}
+			al.rest = rest
+		} else if nameNode, def, ok := optionalParam(n); ok {
+			al.names = append(al.names, nameNode.token.value)
+			al.defaults = append(al.defaults, def)
+		} else if !n.isForm && n.token.kind == tkName {
+			// required parameters may not follow optional ones
+			if len(al.defaults) > 0 && al.defaults[len(al.defaults)-1] != nil {
+				return nil, n
+			}
+			al.names = append(al.names, n.token.value)
+			al.defaults = append(al.defaults, nil)
+		} else {
+			return nil, n
+		}
+	}
+
+	return &al, nil
+}
+
+// bindArgs binds the arguments of a form invocation to the form's
+// parameters in localFrame, evaluating defaults of any missing
+// optional arguments and collecting any rest arguments into a vec.
+//
+// Missing required arguments and extra arguments past the form's
+// parameters are an error, unless lenient is set, in which case extra
+// arguments are ignored. Callbacks are invoked leniently, because forms
+// that take callbacks (like vec::map) idiomatically pass trailing
+// arguments (like the index) that callbacks may not need.
+func bindArgs(localFrame *Frame, params *argList, sc *scopeInfo, args []Value, name string, node *astNode, lenient bool) InterpreterError {
+	if required := params.required(); len(args) < required {
+		return IncorrectNumberOfArgsError{
+			node:     node,
+			name:     name,
+			required: required,
+			given:    len(args),
+			atLeast:  required < len(params.names) || params.rest != "",
+		}
+	}
+	if !lenient && params.rest == "" && len(args) > len(params.names) {
+		return IncorrectNumberOfArgsError{
+			node:     node,
+			name:     name,
+			required: len(params.names),
+			given:    len(args),
+			atMost:   params.required() < len(params.names),
+		}
+	}
+
+	bind := func(i int, val Value) {
+		if sc != nil {
+			localFrame.slots[sc.params[i]] = val
+		} else if i < len(params.names) {
+			localFrame.Put(params.names[i], val)
+		} else {
+			localFrame.Put(params.rest, val)
+		}
+	}
+
+	for i := range params.names {
+		if i < len(args) {
+			bind(i, args[i])
+			continue
+		}
+
+		// defaults may refer to earlier arguments,
+		// so they are evaluated in the local frame
+		val, err := unlazyEval(localFrame, params.defaults[i])
+		if err != nil {
+			return err
+		}
+		bind(i, val)
+	}
+
+	if params.rest != "" {
+		rest := []Value{}
+		if len(args) > len(params.names) {
+			rest = append(rest, args[len(params.names):]...)
+		}
+		bind(len(params.names), NewVecValue(rest))
+	}
+
+	return nil
+}
+
 func evalIfForm(fr *Frame, args []*astNode) (Value, InterpreterError) {
 	if len(args) != 3 {
 		return nil, InvalidIfError{
@@ -262,6 +572,9 @@ func evalIfForm(fr *Frame, args []*astNode) (Value, InterpreterError) {
 		return nil, err
 	}
 
+	if cov := fr.Vm.coverage; cov != nil {
+		cov.branch(condNode, cond)
+	}
 	switch cond {
 	case trueValue:
 		return eval(fr, ifTrueNode)
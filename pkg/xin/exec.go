@@ -0,0 +1,280 @@
+package xin
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	osPath "path"
+	"sync"
+)
+
+// process is a child process started by os::exec. Its exit status is
+// collected by a single goroutine as soon as it exits, so that it is
+// available to any number of wait calls.
+type process struct {
+	cmd *exec.Cmd
+	// done is closed once the process has exited
+	done chan struct{}
+	// exitCode is the exit status of the process, or -1 if it was
+	// terminated by a signal. It is valid once done is closed.
+	exitCode int
+	// err is set if the process could not be waited on
+	err error
+
+	sync.Mutex
+	exited bool
+}
+
+// osExecForm starts a command with a vec of arguments, and an optional
+// map of options: 'env', a map of environment variables to set for the
+// command in addition to those of the Xin process, and 'dir', the
+// directory to run the command in, which defaults to the directory of
+// the running program. It returns a map of the process's 'pid', the
+// streams 'stdin', 'stdout', and 'stderr' connected to it, and the forms
+// 'wait' and 'kill', or an error value if the command could not start.
+func osExecForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	name, ok := args[0].(StringValue)
+	if !ok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	var cmdArgs []string
+	if len(args) >= 2 {
+		argVec, ok := args[1].(VecValue)
+		if !ok {
+			return nil, MismatchedArgumentsError{
+				node: node,
+				args: args,
+			}
+		}
+
+		for _, arg := range argVec.underlying.items {
+			argStr, ok := arg.(StringValue)
+			if !ok {
+				return nil, MismatchedArgumentsError{
+					node: node,
+					args: args,
+				}
+			}
+			cmdArgs = append(cmdArgs, string(argStr))
+		}
+	}
+
+	vm := fr.Vm
+	cmd := exec.Command(string(name), cmdArgs...)
+	cmd.Dir = *fr.cwd
+
+	if len(args) >= 3 {
+		opts, ok := args[2].(MapValue)
+		if !ok {
+			return nil, MismatchedArgumentsError{
+				node: node,
+				args: args,
+			}
+		}
+
+		if !applyExecOptions(cmd, opts, *fr.cwd) {
+			return nil, MismatchedArgumentsError{
+				node: node,
+				args: args,
+			}
+		}
+	}
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		return osErrorValue(err, node), nil
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		stdinR.Close()
+		stdinW.Close()
+		return osErrorValue(err, node), nil
+	}
+	stderrR, stderrW, err := os.Pipe()
+	if err != nil {
+		stdinR.Close()
+		stdinW.Close()
+		stdoutR.Close()
+		stdoutW.Close()
+		return osErrorValue(err, node), nil
+	}
+
+	// the child's ends of the pipes are passed as files, rather than
+	// through exec's own pipes, so that waiting on the process does
+	// not close our ends before the program has read all of its output
+	cmd.Stdin = stdinR
+	cmd.Stdout = stdoutW
+	cmd.Stderr = stderrW
+
+	err = cmd.Start()
+	stdinR.Close()
+	stdoutW.Close()
+	stderrW.Close()
+	if err != nil {
+		stdinW.Close()
+		stdoutR.Close()
+		stderrR.Close()
+		return osErrorValue(err, node), nil
+	}
+
+	proc := &process{
+		cmd:  cmd,
+		done: make(chan struct{}),
+	}
+	go proc.wait()
+
+	procMap := NewMapValue()
+	procMap.set(StringValue("pid"), IntValue(cmd.Process.Pid))
+	procMap.set(StringValue("stdin"), newRWStream(vm, stdinW))
+	procMap.set(StringValue("stdout"), newRWStream(vm, stdoutR))
+	procMap.set(StringValue("stderr"), newRWStream(vm, stderrR))
+	procMap.set(StringValue("wait"), newNativeForm("os::exec::wait", proc.waitForm))
+	procMap.set(StringValue("kill"), newNativeForm("os::exec::kill", proc.killForm))
+
+	return procMap, nil
+}
+
+// applyExecOptions sets the directory and environment of cmd from
+// the options given to os::exec, and reports whether they were valid.
+func applyExecOptions(cmd *exec.Cmd, opts MapValue, cwd string) bool {
+	if dir, prs := (*opts.items)[hashable(StringValue("dir"))]; prs {
+		dirStr, ok := dir.(StringValue)
+		if !ok {
+			return false
+		}
+
+		cmd.Dir = string(dirStr)
+		if !osPath.IsAbs(cmd.Dir) {
+			cmd.Dir = osPath.Join(cwd, cmd.Dir)
+		}
+	}
+
+	if env, prs := (*opts.items)[hashable(StringValue("env"))]; prs {
+		envMap, ok := env.(MapValue)
+		if !ok {
+			return false
+		}
+
+		cmd.Env = os.Environ()
+		for k, v := range *envMap.items {
+			keyStr, kok := k.(hashableStringProxy)
+			valStr, vok := v.(StringValue)
+			if !kok || !vok {
+				return false
+			}
+			cmd.Env = append(cmd.Env, string(keyStr)+"="+string(valStr))
+		}
+	}
+
+	return true
+}
+
+func (p *process) wait() {
+	err := p.cmd.Wait()
+
+	p.Lock()
+	p.exited = true
+	p.Unlock()
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		p.exitCode = exitErr.ExitCode()
+	} else if err != nil {
+		p.err = err
+		p.exitCode = -1
+	} else {
+		p.exitCode = p.cmd.ProcessState.ExitCode()
+	}
+	close(p.done)
+}
+
+// waitForm waits for the process to exit, and calls a callback with its
+// exit status, or an error value if it could not be waited on. Without
+// a callback, it returns a future of the exit status.
+func (p *process) waitForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	var callback Value
+	if len(args) >= 1 {
+		switch args[0].(type) {
+		case FormValue, NativeFormValue:
+			callback = args[0]
+		default:
+			return nil, MismatchedArgumentsError{
+				node: node,
+				args: args,
+			}
+		}
+	}
+
+	vm := fr.Vm
+	ctx := vm.ctx
+	op := vm.loop.start(ctx, "os::exec::wait", node)
+
+	var fut FutureValue
+	var result Value = trueValue
+	if callback == nil {
+		fut = newFutureValue(vm)
+		result = fut
+	}
+
+	go func() {
+		select {
+		case <-p.done:
+		case <-ctx.Done():
+			if callback == nil {
+				fut.fail(interruptedErrorValue(node))
+			}
+			op.complete(nil)
+			return
+		}
+
+		var rv Value = IntValue(p.exitCode)
+		if p.err != nil {
+			rv = osErrorValue(p.err, node)
+		}
+
+		if callback == nil {
+			fut.settleWith(rv, nil)
+			op.complete(nil)
+			return
+		}
+
+		op.complete(func() InterpreterError {
+			_, err := unlazyEvalCallback(fr, callback, []Value{rv}, node)
+			return err
+		})
+	}()
+
+	return result, nil
+}
+
+// killForm kills the process if it is still running. It returns true
+// if it killed the process, false if the process had already exited,
+// or an error value if the process could not be killed.
+func (p *process) killForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.exited {
+		return falseValue, nil
+	}
+
+	// the process may have been reaped by wait, which has yet
+	// to mark it exited, in which case it is already done
+	if err := p.cmd.Process.Kill(); errors.Is(err, os.ErrProcessDone) {
+		return falseValue, nil
+	} else if err != nil {
+		return osErrorValue(err, node), nil
+	}
+	return trueValue, nil
+}
@@ -0,0 +1,181 @@
+package xin
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// fmtNode is a node in the syntax tree of a source file as written,
+// with its comments and line breaks, which the formatter prints.
+type fmtNode struct {
+	isForm bool
+	// tok is the open paren of a form, or the atom or comment
+	tok    sourceToken
+	leaves []*fmtNode
+}
+
+func (n *fmtNode) isComment() bool {
+	return !n.isForm && n.tok.kind == tkComment
+}
+
+// FormatSource formats Xin source code in the canonical layout of the
+// standard library, keeping its comments and the line breaks between
+// forms, and returns the formatted source. Formatting a formatted
+// source does not change it. Sources that do not parse are not
+// formatted, and return the parse error.
+func FormatSource(path string, source string) (string, InterpreterError) {
+	// only format sources that parse, so the
+	// syntax tree of the source is well formed
+	toks, err := lex(path, strings.NewReader(source))
+	if err != nil {
+		return "", err
+	}
+	if _, err := parse(toks); err != nil {
+		return "", err
+	}
+
+	sourceToks, err := lexSource(path, strings.NewReader(source))
+	if err != nil {
+		return "", err
+	}
+
+	root := &fmtNode{
+		isForm: true,
+	}
+	stack := []*fmtNode{root}
+	for _, tok := range sourceToks {
+		parent := stack[len(stack)-1]
+		switch tok.kind {
+		case tkOpenParen:
+			form := &fmtNode{
+				isForm: true,
+				tok:    tok,
+			}
+			parent.leaves = append(parent.leaves, form)
+			stack = append(stack, form)
+		case tkCloseParen:
+			stack = stack[:len(stack)-1]
+		default:
+			parent.leaves = append(parent.leaves, &fmtNode{
+				tok: tok,
+			})
+		}
+	}
+
+	f := formatter{
+		source: source,
+	}
+
+	// newReader skips the shebang line, so it is kept as written
+	if strings.HasPrefix(source, "#!") {
+		f.write(strings.SplitN(source, "\n", 2)[0] + "\n")
+		if len(root.leaves) > 0 && root.leaves[0].tok.newlines > 0 {
+			f.write("\n")
+		}
+	}
+	for i, leaf := range root.leaves {
+		if i > 0 {
+			switch {
+			case leaf.tok.newlines > 1:
+				f.write("\n\n")
+			case leaf.tok.newlines == 1 || !leaf.isComment():
+				f.write("\n")
+			default:
+				f.write(" ")
+			}
+		}
+		f.print(leaf)
+	}
+	if len(root.leaves) > 0 {
+		f.write("\n")
+	}
+
+	return f.String(), nil
+}
+
+// formatter prints fmtNodes, keeping track of the column it is at
+type formatter struct {
+	strings.Builder
+	col    int
+	source string
+}
+
+// sourceCol returns the column of a token in the source
+func (f *formatter) sourceCol(tok sourceToken) int {
+	lineStart := strings.LastIndexByte(f.source[:tok.offset], '\n') + 1
+	return utf8.RuneCountInString(f.source[lineStart:tok.offset])
+}
+
+// bodyIndented reports whether the first leaf of a form that begins a
+// line in the source is indented less than the form's first argument.
+func (f *formatter) bodyIndented(leaves []*fmtNode) bool {
+	for _, leaf := range leaves[2:] {
+		if leaf.tok.newlines > 0 {
+			return f.sourceCol(leaf.tok) < f.sourceCol(leaves[1].tok)
+		}
+	}
+	return false
+}
+
+func (f *formatter) write(s string) {
+	f.WriteString(s)
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		f.col = utf8.RuneCountInString(s[i+1:])
+	} else {
+		f.col += utf8.RuneCountInString(s)
+	}
+}
+
+func (f *formatter) print(node *fmtNode) {
+	if !node.isForm {
+		if node.isComment() {
+			f.write(strings.TrimRight(node.tok.text, " \t\r"))
+		} else {
+			f.write(node.tok.text)
+		}
+		return
+	}
+
+	parenCol := f.col
+	f.write("(")
+
+	leaves := node.leaves
+	// the leaves of a form that do not follow their head on its line
+	// are indented past the head of the form. Bodies of if and do are
+	// indented by two. Arguments to other forms line up under the first
+	// argument, if it is on the same line as the head, unless the source
+	// indents them less, like the bodies of macros.
+	indent := parenCol + 2
+	if len(leaves) > 0 {
+		head := leaves[0]
+		switch {
+		case head.isForm:
+			indent = parenCol + 1
+		case head.tok.kind == tkIfForm || head.tok.kind == tkDoForm:
+		case len(leaves) > 1 && leaves[1].tok.newlines == 0 && !head.isComment():
+			if !f.bodyIndented(leaves) {
+				indent = parenCol + 1 + utf8.RuneCountInString(head.tok.text) + 1
+			}
+		}
+	}
+
+	for i, leaf := range leaves {
+		if i > 0 {
+			switch {
+			case leaf.tok.newlines > 1:
+				f.write("\n\n" + strings.Repeat(" ", indent))
+			case leaf.tok.newlines == 1 || leaves[i-1].isComment():
+				f.write("\n" + strings.Repeat(" ", indent))
+			default:
+				f.write(" ")
+			}
+		}
+		f.print(leaf)
+	}
+
+	// a closing paren can only follow a comment on the next line
+	if len(leaves) > 0 && leaves[len(leaves)-1].isComment() {
+		f.write("\n" + strings.Repeat(" ", indent))
+	}
+	f.write(")")
+}
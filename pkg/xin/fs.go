@@ -0,0 +1,287 @@
+package xin
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// osDirForm lists the entries of a directory as a vec of os::stat maps,
+// sorted by name, and calls a callback with the vec, or an error value
+// if it fails. Without a callback, it returns a future of the vec.
+func osDirForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	firstStr, fok := args[0].(StringValue)
+	callback, cok := callbackArg(args, 1)
+	if !fok || !cok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	return goFs(fr, "os::dir", node, callback, func() Value {
+		infos, err := ioutil.ReadDir(string(firstStr))
+		if err != nil {
+			return osErrorValue(err, node)
+		}
+
+		entries := make([]Value, len(infos))
+		for i, info := range infos {
+			entries[i] = statMap(info)
+		}
+		return NewVecValue(entries)
+	}), nil
+}
+
+// osMkdirForm creates a directory, along with any parent directories
+// that do not exist yet, and calls a callback with 0, or an error value
+// if it fails. Without a callback, it returns a future of 0.
+func osMkdirForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	firstStr, fok := args[0].(StringValue)
+	callback, cok := callbackArg(args, 1)
+	if !fok || !cok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	return goFs(fr, "os::mkdir", node, callback, func() Value {
+		if err := os.MkdirAll(string(firstStr), 0755); err != nil {
+			return osErrorValue(err, node)
+		}
+		return zeroValue
+	}), nil
+}
+
+// osRenameForm renames or moves a file or directory, and calls a
+// callback with 0, or an error value if it fails. Without a callback,
+// it returns a future of 0.
+func osRenameForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 2 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 2,
+			given:    len(args),
+		}
+	}
+
+	firstStr, fok := args[0].(StringValue)
+	secondStr, sok := args[1].(StringValue)
+	callback, cok := callbackArg(args, 2)
+	if !fok || !sok || !cok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	return goFs(fr, "os::rename", node, callback, func() Value {
+		if err := os.Rename(string(firstStr), string(secondStr)); err != nil {
+			return osErrorValue(err, node)
+		}
+		return zeroValue
+	}), nil
+}
+
+// osCopyForm copies a file, or a directory and everything in it, and
+// calls a callback with 0, or an error value if it fails. Without a
+// callback, it returns a future of 0.
+func osCopyForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 2 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 2,
+			given:    len(args),
+		}
+	}
+
+	firstStr, fok := args[0].(StringValue)
+	secondStr, sok := args[1].(StringValue)
+	callback, cok := callbackArg(args, 2)
+	if !fok || !sok || !cok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	return goFs(fr, "os::copy", node, callback, func() Value {
+		if err := copyPath(string(firstStr), string(secondStr)); err != nil {
+			return osErrorValue(err, node)
+		}
+		return zeroValue
+	}), nil
+}
+
+var errCopyIntoItself = errors.New("cannot copy a directory into itself")
+
+// copyPath copies the file or directory at src to dst, copying
+// directories recursively, and keeping the permissions of each file.
+func copyPath(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return copyFile(src, dst, info.Mode().Perm())
+	}
+
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	absDst, err := filepath.Abs(dst)
+	if err != nil {
+		return err
+	}
+	if absDst == absSrc || strings.HasPrefix(absDst, absSrc+string(filepath.Separator)) {
+		return errCopyIntoItself
+	}
+
+	if err := os.MkdirAll(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	infos, err := ioutil.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range infos {
+		err := copyPath(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// osGlobForm finds the paths matching a pattern, like 'samples/*.xin',
+// and calls a callback with a vec of the paths, or an error value if the
+// pattern is malformed. Without a callback, it returns a future of the vec.
+func osGlobForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	firstStr, fok := args[0].(StringValue)
+	callback, cok := callbackArg(args, 1)
+	if !fok || !cok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	return goFs(fr, "os::glob", node, callback, func() Value {
+		matches, err := filepath.Glob(string(firstStr))
+		if err != nil {
+			return osErrorValue(err, node)
+		}
+
+		paths := make([]Value, len(matches))
+		for i, match := range matches {
+			paths[i] = StringValue(match)
+		}
+		return NewVecValue(paths)
+	}), nil
+}
+
+// errWalkStopped stops a walk whose callback raised an error
+var errWalkStopped = errors.New("walk stopped")
+
+// osWalkForm walks the file tree under a path in lexical order, calling
+// a callback with the path and os::stat map of each file and directory in
+// it, including the path itself. Each callback runs before the walk goes
+// on to the next entry. Once the walk is done, it calls a second callback
+// with 0, or an error value if the walk or a callback failed. Without
+// a second callback, it returns a future of 0.
+func osWalkForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 2 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 2,
+			given:    len(args),
+		}
+	}
+
+	firstStr, fok := args[0].(StringValue)
+	visit, vok := callbackArg(args, 1)
+	callback, cok := callbackArg(args, 2)
+	if !fok || !vok || !cok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	vm := fr.Vm
+	ctx := vm.ctx
+	return goFs(fr, "os::walk", node, callback, func() Value {
+		var visitErr InterpreterError
+		err := filepath.Walk(string(firstStr), func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			visitErr = vm.loop.call(ctx, "os::walk", node, func() InterpreterError {
+				_, err := unlazyEvalCallback(fr, visit, []Value{StringValue(path), statMap(info)}, node)
+				return err
+			})
+			if visitErr != nil {
+				return errWalkStopped
+			}
+			return nil
+		})
+
+		if visitErr != nil {
+			return errorValueFromError(visitErr)
+		} else if err != nil {
+			return osErrorValue(err, node)
+		}
+		return zeroValue
+	}), nil
+}
@@ -0,0 +1,407 @@
+package xin
+
+import (
+	"context"
+	"sync"
+)
+
+// FutureValue is the eventual result of an async operation. A future
+// is pending until the operation is done, then settles once, either
+// resolving to a value or failing with an error value.
+type FutureValue struct {
+	underlying *futureState
+}
+
+type futureState struct {
+	// vm is the Vm that created the future, in the evaluation with
+	// the context ctx. It reports failures that nothing handles.
+	vm  *Vm
+	ctx context.Context
+
+	sync.Mutex
+	done    bool
+	failed  bool
+	value   Value
+	waiters []func(Value, bool)
+	// handled is set once anything has waited on the future
+	handled bool
+}
+
+// newFutureValue creates a pending future. It must be
+// called with the Vm locked, like any native form.
+func newFutureValue(vm *Vm) FutureValue {
+	return FutureValue{
+		underlying: &futureState{
+			vm:  vm,
+			ctx: vm.ctx,
+		},
+	}
+}
+
+func (v FutureValue) String() string {
+	s := v.underlying
+
+	s.Lock()
+	defer s.Unlock()
+
+	switch {
+	case !s.done:
+		return "(<future> pending)"
+	case s.failed:
+		return "(<future> failed " + s.value.String() + ")"
+	default:
+		return "(<future> " + s.value.String() + ")"
+	}
+}
+
+func (v FutureValue) Repr() string {
+	return v.String()
+}
+
+func (v FutureValue) Equal(o Value) bool {
+	if ov, ok := o.(FutureValue); ok {
+		return v.underlying == ov.underlying
+	}
+
+	return false
+}
+
+// settle resolves the future to val, or fails it with val if failed
+// is set. Futures settle off the event loop, as soon as their operation
+// is done. Only the first call to settle has any effect.
+func (v FutureValue) settle(val Value, failed bool) {
+	s := v.underlying
+
+	s.Lock()
+	if s.done {
+		s.Unlock()
+		return
+	}
+	s.done = true
+	s.failed = failed
+	s.value = val
+	waiters := s.waiters
+	s.waiters = nil
+	s.Unlock()
+
+	// like an error raised in an async callback, a failure that
+	// nothing waits on is reported, unless the program waits on
+	// the future before the event loop gets to the report
+	if failed && len(waiters) == 0 {
+		if errVal, ok := val.(ErrorValue); ok && errVal.kind != "interrupted" {
+			s.vm.loop.start(s.ctx, "future", nil).complete(func() InterpreterError {
+				s.Lock()
+				defer s.Unlock()
+
+				if !s.handled {
+					return raisedError{value: errVal}
+				}
+				return nil
+			})
+		}
+	}
+
+	for _, waiter := range waiters {
+		waiter(val, failed)
+	}
+}
+
+func (v FutureValue) resolve(val Value) {
+	v.settle(val, false)
+}
+
+// fail fails the future with an error value. Operations that fail with
+// an ErrorValue, like a read from a closed file, fail their futures.
+func (v FutureValue) fail(err ErrorValue) {
+	v.settle(err, true)
+}
+
+// settleWith settles the future with the outcome of an operation.
+func (v FutureValue) settleWith(val Value, err InterpreterError) {
+	if err != nil {
+		v.fail(errorValueFromError(err))
+	} else if errVal, ok := val.(ErrorValue); ok {
+		v.fail(errVal)
+	} else {
+		v.resolve(val)
+	}
+}
+
+// listen calls waiter once the future has settled, or right away
+// if it already has. waiter may be called from any goroutine.
+func (v FutureValue) listen(waiter func(Value, bool)) {
+	s := v.underlying
+
+	s.Lock()
+	s.handled = true
+	if !s.done {
+		s.waiters = append(s.waiters, waiter)
+		s.Unlock()
+		return
+	}
+	val, failed := s.value, s.failed
+	s.Unlock()
+
+	waiter(val, failed)
+}
+
+// onSettle runs callback on the event loop of vm once the future has
+// settled. The future it returns settles with the result of callback,
+// or with the future callback returns, and fails if the result is an
+// error value. If the evaluation is cancelled before callback can run,
+// the returned future fails.
+func (v FutureValue) onSettle(vm *Vm, name string, node *astNode,
+	callback func(Value, bool) (Value, InterpreterError)) FutureValue {
+	next := newFutureValue(vm)
+
+	op := vm.loop.start(vm.ctx, name, node)
+	op.onCancel = func() {
+		next.fail(interruptedErrorValue(node))
+	}
+	v.listen(func(val Value, failed bool) {
+		op.complete(func() InterpreterError {
+			result, err := callback(val, failed)
+			if err != nil {
+				next.settleWith(nil, err)
+			} else if resultFuture, ok := result.(FutureValue); ok {
+				resultFuture.listen(next.settle)
+			} else {
+				next.settleWith(result, nil)
+			}
+			return nil
+		})
+	})
+
+	return next
+}
+
+func interruptedErrorValue(node *astNode) ErrorValue {
+	return ErrorValue{
+		message:  "Evaluation interrupted",
+		kind:     "interrupted",
+		position: node.position,
+	}
+}
+
+// futureForm returns a future already settled with its argument,
+// which fails if the argument is an error value.
+func futureForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	fut := newFutureValue(fr.Vm)
+	if len(args) < 1 {
+		fut.resolve(zeroValue)
+	} else if argFuture, ok := args[0].(FutureValue); ok {
+		return argFuture, nil
+	} else {
+		fut.settleWith(args[0], nil)
+	}
+
+	return fut, nil
+}
+
+// futureThenForm calls a form with the value of a future once it
+// resolves, and returns a future of the form's result. If the future
+// fails, the form is not called, and the returned future fails too.
+func futureThenForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 2 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 2,
+			given:    len(args),
+		}
+	}
+
+	first, second := args[0], args[1]
+
+	firstFuture, fok := first.(FutureValue)
+	switch second.(type) {
+	case FormValue, NativeFormValue:
+	default:
+		fok = false
+	}
+	if !fok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	return firstFuture.onSettle(fr.Vm, "future::then", node, func(val Value, failed bool) (Value, InterpreterError) {
+		if failed {
+			return nil, raisedError{value: val.(ErrorValue)}
+		}
+		return unlazyEvalCallback(fr, second, []Value{val}, node)
+	}), nil
+}
+
+// futureCatchForm calls a form with the error of a future if it fails,
+// and returns a future of the form's result. If the future resolves,
+// the returned future resolves to the same value.
+func futureCatchForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 2 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 2,
+			given:    len(args),
+		}
+	}
+
+	first, second := args[0], args[1]
+
+	firstFuture, fok := first.(FutureValue)
+	switch second.(type) {
+	case FormValue, NativeFormValue:
+	default:
+		fok = false
+	}
+	if !fok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	return firstFuture.onSettle(fr.Vm, "future::catch", node, func(val Value, failed bool) (Value, InterpreterError) {
+		if !failed {
+			return val, nil
+		}
+		return unlazyEvalCallback(fr, second, []Value{val}, node)
+	}), nil
+}
+
+// futuresOf returns the items of a vec as futures. Items that
+// are not futures are treated as futures settled with the item.
+func futuresOf(vm *Vm, args []Value, node *astNode) ([]FutureValue, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	firstVec, ok := args[0].(VecValue)
+	if !ok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	futures := make([]FutureValue, len(firstVec.underlying.items))
+	for i, item := range firstVec.underlying.items {
+		if itemFuture, ok := item.(FutureValue); ok {
+			futures[i] = itemFuture
+		} else {
+			futures[i] = newFutureValue(vm)
+			futures[i].settleWith(item, nil)
+		}
+	}
+
+	return futures, nil
+}
+
+// futureAllForm returns a future of a vec of the values of a vec of
+// futures, in order, once they all resolve. If any of the futures
+// fails, the returned future fails with its error.
+func futureAllForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	futures, err := futuresOf(fr.Vm, args, node)
+	if err != nil {
+		return nil, err
+	}
+
+	all := newFutureValue(fr.Vm)
+	if len(futures) == 0 {
+		all.resolve(NewVecValue([]Value{}))
+		return all, nil
+	}
+
+	var lock sync.Mutex
+	values := make([]Value, len(futures))
+	remaining := len(futures)
+	for i, fut := range futures {
+		i := i
+		fut.listen(func(val Value, failed bool) {
+			if failed {
+				all.settle(val, true)
+				return
+			}
+
+			lock.Lock()
+			values[i] = val
+			remaining--
+			done := remaining == 0
+			lock.Unlock()
+
+			if done {
+				all.resolve(NewVecValue(values))
+			}
+		})
+	}
+
+	return all, nil
+}
+
+// futureRaceForm returns a future that settles like the first of
+// a vec of futures to settle.
+func futureRaceForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	futures, err := futuresOf(fr.Vm, args, node)
+	if err != nil {
+		return nil, err
+	}
+
+	first := newFutureValue(fr.Vm)
+	for _, fut := range futures {
+		fut.listen(first.settle)
+	}
+
+	return first, nil
+}
+
+// futureWaitForm waits for a future to settle, and evaluates to its
+// value, or raises its error if it fails. Other callbacks may run on
+// the event loop while the program waits.
+func futureWaitForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	firstFuture, ok := args[0].(FutureValue)
+	if !ok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	var result Value
+	var failed bool
+	done := make(chan struct{})
+	firstFuture.listen(func(val Value, f bool) {
+		result, failed = val, f
+		close(done)
+	})
+
+	vm := fr.Vm
+	ctx := vm.ctx
+	vm.block(func() {
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+	})
+
+	if ctx.Err() != nil {
+		return nil, InterruptedError{
+			position: node.position,
+		}
+	}
+	if failed {
+		return nil, raisedError{value: result.(ErrorValue)}
+	}
+
+	return result, nil
+}
@@ -14,6 +14,7 @@ const (
 	tkCloseParen
 
 	tkBindForm
+	tkMacroForm
 	tkIfForm
 	tkDoForm
 	tkImportForm
@@ -23,6 +24,13 @@ const (
 	tkNumberLiteralDecimal
 	tkNumberLiteralHex
 	tkStringLiteral
+
+	// tkValueLiteral is never lexed from source, but appears in
+	// syntax synthesized by macros to embed an existing Value
+	tkValueLiteral
+
+	// tkComment only appears in lossless token streams
+	tkComment
 )
 
 type tokenKind int
@@ -36,6 +44,9 @@ type token struct {
 	// number literals at parse time for runtime efficiency
 	intv  IntValue
 	fracv FracValue
+
+	// value embedded in a tkValueLiteral
+	literal Value
 }
 
 func (tk token) String() string {
@@ -46,16 +57,20 @@ func (tk token) String() string {
 		return ")"
 	case tkBindForm:
 		return ":"
+	case tkMacroForm:
+		return "::"
 	case tkIfForm:
 		return "if"
 	case tkDoForm:
 		return "do"
 	case tkImportForm:
 		return "import"
-	case tkName, tkNumberLiteralInt, tkNumberLiteralDecimal, tkNumberLiteralHex:
+	case tkName, tkNumberLiteralInt, tkNumberLiteralDecimal, tkNumberLiteralHex, tkComment:
 		return tk.value
 	case tkStringLiteral:
 		return "'" + tk.value + "'"
+	case tkValueLiteral:
+		return tk.literal.String()
 	default:
 		return "unknown token"
 	}
@@ -177,6 +192,11 @@ func bufToToken(s string, pos position) token {
 				kind:     tkBindForm,
 				position: pos,
 			}
+		case "::":
+			return token{
+				kind:     tkMacroForm,
+				position: pos,
+			}
 		case "if":
 			return token{
 				kind:     tkIfForm,
@@ -202,17 +222,60 @@ func bufToToken(s string, pos position) token {
 	}
 }
 
+// sourceToken is a token in a lossless token stream of a source file,
+// which keeps comments, the source text of each token, and the line
+// breaks between tokens, so that source can be rewritten without
+// losing any of it.
+type sourceToken struct {
+	token
+	text string
+	// offset is the index of the token's first byte in the source
+	offset int
+	// newlines counts the line breaks between
+	// the previous token and this one
+	newlines int
+}
+
 func lex(path string, r io.Reader) (tokenStream, InterpreterError) {
-	toks := make([]token, 0)
+	sourceToks, err := lexSource(path, r)
+	if err != nil {
+		return nil, err
+	}
+
+	toks := make([]token, 0, len(sourceToks))
+	for _, tok := range sourceToks {
+		if tok.kind != tkComment {
+			toks = append(toks, tok.token)
+		}
+	}
+
+	return toks, nil
+}
+
+// lexSource lexes source into a lossless token stream. Tokens other
+// than comments are the same as those lex returns.
+func lexSource(path string, r io.Reader) ([]sourceToken, InterpreterError) {
+	toks := make([]sourceToken, 0)
 	rdr, err := newReader(path, r)
 	if err != nil {
 		return toks, nil
 	}
 
+	newlines := 0
+	push := func(tok token, text string, offset int) {
+		toks = append(toks, sourceToken{
+			token:    tok,
+			text:     text,
+			offset:   offset,
+			newlines: newlines,
+		})
+		newlines = 0
+	}
+
 	buf := ""
 	clear := func() {
 		if buf != "" {
-			toks = append(toks, bufToToken(buf, rdr.currPos()))
+			push(bufToToken(buf, rdr.currPos()), buf, rdr.index-len(buf))
 			buf = ""
 		}
 	}
@@ -221,10 +284,21 @@ func lex(path string, r io.Reader) (tokenStream, InterpreterError) {
 		switch {
 		case peeked == ";":
 			clear()
-			rdr.upto("\n")
+			start := rdr.index
+			pos := rdr.currPos()
+			comment := rdr.upto("\n")
+			push(token{
+				kind:     tkComment,
+				value:    comment,
+				position: pos,
+			}, comment, start)
+			if !rdr.done() {
+				newlines++
+			}
 			rdr.skip()
 		case peeked == "'":
 			clear()
+			start := rdr.index
 			rdr.skip()
 
 			content := rdr.upto("'")
@@ -249,28 +323,35 @@ func lex(path string, r io.Reader) (tokenStream, InterpreterError) {
 				content += rdr.upto("'")
 			}
 
-			toks = append(toks, token{
+			end := rdr.index + 1
+			if end > rdr.max {
+				end = rdr.max
+			}
+			push(token{
 				kind:     tkStringLiteral,
 				value:    escapeString(content),
 				position: rdr.currPos(),
-			})
+			}, rdr.source[start:end], start)
 			rdr.skip()
 		case peeked == "(":
 			clear()
-			toks = append(toks, token{
+			push(token{
 				kind:     tkOpenParen,
 				position: rdr.currPos(),
-			})
+			}, peeked, rdr.index)
 			rdr.skip()
 		case peeked == ")":
 			clear()
-			toks = append(toks, token{
+			push(token{
 				kind:     tkCloseParen,
 				position: rdr.currPos(),
-			})
+			}, peeked, rdr.index)
 			rdr.skip()
 		case unicode.IsSpace([]rune(peeked)[0]):
 			clear()
+			if peeked == "\n" {
+				newlines++
+			}
 			rdr.skip()
 		default:
 			buf += rdr.next()
@@ -9,35 +9,55 @@ import (
 	_ "github.com/thesephist/xin/statik"
 )
 
-func loadStandardLibrary(vm *Vm) InterpreterError {
-	statikFs, err := fs.New()
-	if err != nil {
-		fmt.Println("Standard library error:", err.Error())
+// stdlibFiles are the files of the standard library. Import order
+// matters here, later libs have dependency on the preceding ones
+var stdlibFiles = []string{
+	"std",
+	"math",
+	"vec",
+	"map",
+	"str",
+	"src",
+	"stat",
+	"os",
+	"test",
+}
+
+// stdlibAlias is the prefix of the names bound in a standard library file
+func stdlibAlias(path string) string {
+	// everything in std.xin is assumed not to be prefixed
+	if path == "std" {
+		return ""
+	}
+	return path
+}
+
+// stdlibPath is the path given to the forms of a standard library
+// file, like "(std) vec", or just "(std)" for std.xin
+func stdlibPath(path string) string {
+	if alias := stdlibAlias(path); alias != "" {
+		return stdPathPrefix + " " + alias
 	}
+	return stdPathPrefix
+}
 
-	// import order matters here, later libs
-	// have dependency on the preceding ones
-	libFiles := []string{
-		"std",
-		"math",
-		"vec",
-		"map",
-		"str",
-		"src",
-		"stat",
-		"os",
-		"test",
+// loadStandardLibrary evaluates the standard library and binds its names
+// in the frame std. Each file is evaluated in its own frame, which like
+// std is shared with the tasks the Vm spawns, so neither may be changed
+// once the standard library is loaded.
+func loadStandardLibrary(vm *Vm, std *Frame) InterpreterError {
+	statikFs, err := fs.New()
+	if err != nil {
+		return RuntimeError{
+			reason: fmt.Sprintf("Stdlib error: %s", err.Error()),
+		}
 	}
 
 	vm.Lock()
 	defer vm.Unlock()
 
-	for _, path := range libFiles {
-		alias := path
-		// everything in std.xin is assumed not to be prefixed
-		if path == "std" {
-			alias = ""
-		}
+	for _, path := range stdlibFiles {
+		alias := stdlibAlias(path)
 
 		libFile, ferr := statikFs.Open("/" + path + ".xin")
 		if ferr != nil {
@@ -48,7 +68,7 @@ func loadStandardLibrary(vm *Vm) InterpreterError {
 		defer libFile.Close()
 
 		// std import
-		toks, err := lex(fmt.Sprintf("(std) %s", alias), libFile)
+		toks, err := lex(stdlibPath(path), libFile)
 		if err != nil {
 			return err
 		}
@@ -56,21 +76,22 @@ func loadStandardLibrary(vm *Vm) InterpreterError {
 		if err != nil {
 			return err
 		}
+		resolveNames(&rootNode, nil)
 
-		libFrame := newFrame(vm.Frame)
+		libFrame := newFrame(std)
+		libFrame.shared = true
 		_, err = unlazyEval(libFrame, &rootNode)
 		if err != nil {
 			return err
 		}
 
-		rootFrame := vm.Frame
 		if alias == "" {
 			for name, value := range libFrame.Scope {
-				rootFrame.Put(name, value)
+				std.Put(name, value)
 			}
 		} else {
 			for name, value := range libFrame.Scope {
-				rootFrame.Put(alias+"::"+name, value)
+				std.Put(alias+"::"+name, value)
 			}
 		}
 	}
@@ -93,12 +114,14 @@ func importFrame(fr *Frame, importPath string) (*Frame, InterpreterError) {
 	if err != nil {
 		return nil, err
 	}
+	resolveNames(&rootNode, nil)
 
 	// import runs in a new top-level frame in
 	// the same VM (execution lock)
 	importFrame := newFrame(fr.Vm.Frame)
 	importCwd := osPath.Dir(importPath)
 	importFrame.cwd = &importCwd
+	fr.Vm.cwds = append(fr.Vm.cwds, &importCwd)
 	_, err = unlazyEval(importFrame, &rootNode)
 	if err != nil {
 		return nil, err
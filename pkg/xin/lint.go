@@ -0,0 +1,241 @@
+package xin
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// Severities of lint issues
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// LintIssue is a problem found by a Linter in a Xin source file.
+// Lines and columns are counted from 1.
+type LintIssue struct {
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	Col      int    `json:"col"`
+	Severity string `json:"severity"`
+	// Rule names the check that found the issue, like "undefined"
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (li LintIssue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s (%s)", li.Path, li.Line, li.Col, li.Severity, li.Message, li.Rule)
+}
+
+// Linter checks Xin source files for common mistakes without running
+// them, with the same static analysis as the language server. It finds
+// references to undefined names, imports of missing files, calls to
+// forms with too few or too many arguments, if forms without exactly
+// three branches, unused local bindings, and bindings that shadow
+// names in the standard library.
+type Linter struct {
+	globals map[string]bool
+	stdlib  map[string]stdlibEntry
+	// defs are where the standard library binds its names,
+	// to recognize them when the standard library is linted
+	defs map[stdlibDef]bool
+}
+
+// stdlibDef is the position of a binding in a standard library file
+type stdlibDef struct {
+	source    string
+	line, col int
+}
+
+// NewLinter creates a Linter that knows the names bound in a new Vm
+func NewLinter() (*Linter, error) {
+	globals, stdlib, err := loadStdlibNames()
+	if err != nil {
+		return nil, err
+	}
+
+	defs := make(map[stdlibDef]bool)
+	for _, entry := range stdlib {
+		if b := entry.binding; b != nil {
+			defs[stdlibDef{b.source.source, b.node.line, b.node.col}] = true
+		}
+	}
+
+	return &Linter{
+		globals: globals,
+		stdlib:  stdlib,
+		defs:    defs,
+	}, nil
+}
+
+// Lint checks the Xin source file at path, with contents source, and
+// returns the issues found in order. Files that do not parse report
+// only the parse error.
+func (l *Linter) Lint(path string, source string) []LintIssue {
+	sa := analyzeSource(path, source, l.globals)
+
+	issues := []LintIssue{}
+	report := func(line, col int, severity, rule, message string) {
+		issues = append(issues, LintIssue{
+			Path:     path,
+			Line:     line + 1,
+			Col:      utf8.RuneCountInString(sa.lines[line][:col]) + 1,
+			Severity: severity,
+			Rule:     rule,
+			Message:  message,
+		})
+	}
+	reportName := func(node *astNode, severity, rule, message string) {
+		line, start, _ := sa.nameOffset(node)
+		report(line, start, severity, rule, message)
+	}
+	reportForm := func(node *astNode, severity, rule, message string) {
+		line, col := sa.sourceOffset(node.position)
+		report(line, col, severity, rule, message)
+	}
+
+	if sa.err != nil {
+		line, col := sa.sourceOffset(sa.err.pos())
+		report(line, col, LintError, "syntax", sa.err.Error())
+		return issues
+	}
+
+	for _, node := range sa.undefined {
+		reportName(node, LintError, "undefined", UndefinedNameError{name: node.token.value}.Error())
+	}
+
+	for _, imp := range sa.imports {
+		if imp.path != "" && imp.source == nil {
+			reportForm(imp.node, LintError, "import",
+				fmt.Sprintf("Could not open imported file %s", imp.path))
+		}
+	}
+
+	for _, c := range sa.calls {
+		if severity, message := l.checkArgs(c); message != "" {
+			reportName(c.node.leaves[0], severity, "args", message)
+		}
+	}
+
+	for _, node := range sa.invalidIfs {
+		reportForm(node, LintError, "if",
+			fmt.Sprintf("Invalid if form %s: requires a condition and 2 branches but got %d args",
+				node, len(node.leaves)-1))
+	}
+
+	statements := make(map[*astNode]bool)
+	findStatements(sa.root, statements)
+	for _, b := range sa.bindings {
+		if !b.global && b.kind != bindParam && b.refs == 0 && statements[b.form] {
+			reportName(b.node, LintWarning, "unused",
+				fmt.Sprintf("%s is bound but never used", b.name))
+		}
+
+		if l.shadows(b) {
+			reportName(b.node, LintWarning, "shadow",
+				fmt.Sprintf("%s shadows a name in the standard library", b.name))
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Col < issues[j].Col
+	})
+	return issues
+}
+
+// checkArgs checks the number of arguments in a call to a form defined
+// in Xin, and returns the severity and message of an issue, if any.
+// Missing and extra arguments are both errors at runtime.
+func (l *Linter) checkArgs(c call) (string, string) {
+	name := c.node.leaves[0].token.value
+	b := c.binding
+	if b == nil {
+		b = l.stdlib[name].binding
+	}
+	if b == nil || (b.kind != bindForm && b.kind != bindMacro) {
+		return "", ""
+	}
+
+	params, invalid := parseArgList(b.form.leaves[1].leaves[1:])
+	if invalid != nil {
+		return "", ""
+	}
+
+	given := len(c.node.leaves) - 1
+	if required := params.required(); given < required {
+		return LintError, IncorrectNumberOfArgsError{
+			node:     c.node,
+			name:     name,
+			required: required,
+			given:    given,
+			atLeast:  required < len(params.names) || params.rest != "",
+		}.Error()
+	}
+	if params.rest == "" && given > len(params.names) {
+		return LintError, IncorrectNumberOfArgsError{
+			node:     c.node,
+			name:     name,
+			required: len(params.names),
+			given:    given,
+			atMost:   params.required() < len(params.names),
+		}.Error()
+	}
+	return "", ""
+}
+
+// shadows reports whether a binding shadows a builtin, or a name
+// defined at the top level of the standard library. Names bound
+// within the standard library's definitions, like the m in
+// (: to-dec-number-map (do (: m (map)) ...)), leak into the global
+// frame too, but are not meant to be used.
+func (l *Linter) shadows(b *binding) bool {
+	if !l.globals[b.name] {
+		return false
+	}
+
+	// the standard library defines its own names when it is
+	// linted, like map in vec.xin, and does not shadow them
+	if b.global && l.defs[stdlibDef{b.source.source, b.node.line, b.node.col}] {
+		return false
+	}
+
+	entry, prs := l.stdlib[b.name]
+	if !prs || entry.binding == nil {
+		return true
+	}
+	return isTopLevel(entry.binding)
+}
+
+// isTopLevel reports whether a binding is bound
+// by a form at the top level of its file
+func isTopLevel(b *binding) bool {
+	for _, leaf := range b.source.root.leaves {
+		if leaf == b.form {
+			return true
+		}
+	}
+	return false
+}
+
+// findStatements finds the forms evaluated only for their effects,
+// which are the leaves of do forms other than the last
+func findStatements(node *astNode, statements map[*astNode]bool) {
+	if !node.isForm || len(node.leaves) == 0 {
+		return
+	}
+
+	head := node.leaves[0]
+	if !head.isForm && head.token.kind == tkDoForm && len(node.leaves) > 2 {
+		for _, leaf := range node.leaves[1 : len(node.leaves)-1] {
+			statements[leaf] = true
+		}
+	}
+
+	for _, leaf := range node.leaves {
+		findStatements(leaf, statements)
+	}
+}
@@ -0,0 +1,338 @@
+package xin
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// eventLoop runs the async callbacks of a Vm. Async natives like
+// os::wait and -> start an asyncOp, do their blocking work off the
+// event loop, then submit a completion to the loop's queue. The loop
+// runs completions one at a time, in the order they were submitted,
+// so callbacks never race each other for the Vm.
+//
+// The loop has no goroutine of its own while its queue is empty, and
+// a Vm's evaluation is done once no async operations are pending. A
+// callback that blocks, like one calling future::wait, hands the loop
+// off to a new goroutine, because what it waits for may need the loop.
+// Callbacks still run one at a time, since each holds the Vm lock.
+type eventLoop struct {
+	vm *Vm
+
+	sync.Mutex
+	queue []completion
+	// running is set while a goroutine is draining the queue
+	running bool
+	ops     map[*asyncOp]bool
+	// empty is closed when the last pending op completes
+	empty chan struct{}
+
+	// drainer identifies the goroutine draining the queue, active
+	// the one running a callback with the Vm locked, or 0 if none
+	// is, and drainers counts the goroutines started to drain it
+	drainer, active, drainers int
+
+	// queued work for streams, by stream id, to run stream
+	// operations on the same stream in the order they were made
+	streams map[int64][]func()
+
+	// errors reported by async callbacks since the
+	// last call to Vm.AsyncErrors
+	errors []InterpreterError
+}
+
+// asyncOp is an async operation started by a native form, whose
+// callback has not yet run.
+type asyncOp struct {
+	loop *eventLoop
+	name string
+	node *astNode
+	// ctx is the context of the evaluation that started the op.
+	// If it is cancelled, the op's callback is dropped.
+	ctx     context.Context
+	started time.Time
+	queued  bool
+	// onCancel, if set, runs in place of the op's
+	// callback if its evaluation is cancelled
+	onCancel func()
+}
+
+type completion struct {
+	op *asyncOp
+	// callback runs on the event loop with the Vm locked,
+	// and may be nil if the op has nothing left to run.
+	callback func() InterpreterError
+}
+
+// AsyncOp describes an async operation pending in a Vm, like a timer
+// started by os::wait or a read from a stream.
+type AsyncOp struct {
+	// Name is the name of the form that started the operation
+	Name string
+	// Position is the source position of the form
+	Position string
+	Started  time.Time
+	// Queued is set once the operation is done, and its
+	// callback is waiting for its turn on the event loop
+	Queued bool
+}
+
+func newEventLoop(vm *Vm) *eventLoop {
+	return &eventLoop{
+		vm:      vm,
+		ops:     make(map[*asyncOp]bool),
+		streams: make(map[int64][]func()),
+	}
+}
+
+// start registers a new pending async operation, started by the
+// form name at node in an evaluation with the context ctx.
+func (l *eventLoop) start(ctx context.Context, name string, node *astNode) *asyncOp {
+	l.Lock()
+	defer l.Unlock()
+
+	if len(l.ops) == 0 {
+		l.empty = make(chan struct{})
+	}
+
+	op := &asyncOp{
+		loop:    l,
+		name:    name,
+		node:    node,
+		ctx:     ctx,
+		started: time.Now(),
+	}
+	l.ops[op] = true
+	return op
+}
+
+// complete submits the callback of an op to the event loop. Every op
+// must be completed exactly once, with a nil callback if there is
+// nothing left to run, for the Vm to finish evaluating.
+func (op *asyncOp) complete(callback func() InterpreterError) {
+	l := op.loop
+
+	l.Lock()
+	defer l.Unlock()
+
+	op.queued = true
+	l.queue = append(l.queue, completion{
+		op:       op,
+		callback: callback,
+	})
+	if !l.running {
+		l.running = true
+		l.startDrainer()
+	}
+}
+
+// startDrainer starts a new goroutine to drain the queue, which
+// takes over from any earlier one. It must be called with l locked.
+func (l *eventLoop) startDrainer() {
+	l.drainers++
+	l.drainer = l.drainers
+	go l.run(l.drainer)
+}
+
+// handOff is called before the Vm lock is released to block. If a
+// callback is blocking, the loop is handed off to a new goroutine, so
+// that other callbacks, like those the callback waits for, can run.
+// It returns what resume needs to restore once the Vm is locked again.
+func (l *eventLoop) handOff() int {
+	l.Lock()
+	defer l.Unlock()
+
+	active := l.active
+	if active != 0 && active == l.drainer {
+		l.startDrainer()
+	}
+	l.active = 0
+	return active
+}
+
+// resume restores the running callback after the Vm is locked again.
+func (l *eventLoop) resume(active int) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.active = active
+}
+
+// run drains the queue of completions, running each callback
+// with the Vm locked, unless its evaluation was cancelled. It stops
+// once the loop has been handed off to another drainer.
+func (l *eventLoop) run(drainer int) {
+	for {
+		l.Lock()
+		if l.drainer != drainer {
+			l.Unlock()
+			return
+		}
+		if len(l.queue) == 0 {
+			l.running = false
+			l.Unlock()
+			return
+		}
+		next := l.queue[0]
+		l.queue[0] = completion{}
+		l.queue = l.queue[1:]
+		l.Unlock()
+
+		if next.op.ctx.Err() != nil {
+			abandon(next.op.ctx)
+			if next.op.onCancel != nil {
+				next.op.onCancel()
+			}
+		} else if next.callback != nil {
+			l.vm.Lock()
+			l.resume(drainer)
+			if err := next.callback(); err != nil {
+				l.vm.reportError(err)
+			}
+			l.resume(0)
+			l.vm.Unlock()
+		}
+
+		l.Lock()
+		delete(l.ops, next.op)
+		if len(l.ops) == 0 {
+			close(l.empty)
+		}
+		l.Unlock()
+	}
+}
+
+// drained returns a channel that is closed once
+// no more async operations are pending.
+func (l *eventLoop) drained() <-chan struct{} {
+	l.Lock()
+	defer l.Unlock()
+
+	if len(l.ops) == 0 {
+		closed := make(chan struct{})
+		close(closed)
+		return closed
+	}
+	return l.empty
+}
+
+// goStream runs work off the event loop after any earlier
+// work for the same stream, so that operations on a stream
+// happen in the order they were made.
+func (l *eventLoop) goStream(stream StreamValue, work func()) {
+	l.Lock()
+	defer l.Unlock()
+
+	id := stream.id
+	if queued, prs := l.streams[id]; prs {
+		l.streams[id] = append(queued, work)
+		return
+	}
+
+	l.streams[id] = []func(){work}
+	go func() {
+		for {
+			l.Lock()
+			queued := l.streams[id]
+			if len(queued) == 0 {
+				delete(l.streams, id)
+				l.Unlock()
+				return
+			}
+			next := queued[0]
+			l.streams[id] = queued[1:]
+			l.Unlock()
+
+			next()
+		}
+	}()
+}
+
+// flush waits for the work queued for a stream so far to finish.
+func (l *eventLoop) flush(stream StreamValue) {
+	done := make(chan struct{})
+	l.goStream(stream, func() {
+		close(done)
+	})
+	<-done
+}
+
+// call runs fn on the event loop and waits for it to return. It is
+// used to run Xin callbacks belonging to this Vm from outside of it,
+// so it must not be called with the Vm locked.
+func (l *eventLoop) call(ctx context.Context, name string, node *astNode, fn func() InterpreterError) InterpreterError {
+	result := make(chan InterpreterError, 1)
+	op := l.start(ctx, name, node)
+	op.complete(func() InterpreterError {
+		result <- fn()
+		return nil
+	})
+
+	select {
+	case err := <-result:
+		return err
+	case <-op.ctx.Done():
+		return InterruptedError{
+			position: node.position,
+		}
+	}
+}
+
+// maxAsyncErrors bounds the errors a Vm keeps for AsyncErrors,
+// so long-running programs that are never asked for them do not
+// collect errors without bound.
+const maxAsyncErrors = 100
+
+func (l *eventLoop) reportError(err InterpreterError) {
+	l.Lock()
+	defer l.Unlock()
+
+	if len(l.errors) == maxAsyncErrors {
+		l.errors = l.errors[1:]
+	}
+	l.errors = append(l.errors, err)
+}
+
+// PendingOps lists the async operations pending in the Vm,
+// in the order they were started.
+func (vm *Vm) PendingOps() []AsyncOp {
+	l := vm.loop
+
+	l.Lock()
+	defer l.Unlock()
+
+	ops := make([]AsyncOp, 0, len(l.ops))
+	for op := range l.ops {
+		pos := ""
+		if op.node != nil {
+			pos = op.node.position.String()
+		}
+		ops = append(ops, AsyncOp{
+			Name:     op.name,
+			Position: pos,
+			Started:  op.started,
+			Queued:   op.queued,
+		})
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].Started.Before(ops[j].Started)
+	})
+	return ops
+}
+
+// AsyncErrors returns the errors raised by async callbacks since
+// the last call to AsyncErrors, up to the latest 100. These errors
+// have no caller to be returned to, so they are also printed to the
+// Vm's stderr.
+func (vm *Vm) AsyncErrors() []InterpreterError {
+	l := vm.loop
+
+	l.Lock()
+	defer l.Unlock()
+
+	errs := l.errors
+	l.errors = nil
+	return errs
+}
@@ -0,0 +1,583 @@
+package xin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// LSP error codes and enumerations used by the language server
+const (
+	lspMethodNotFound = -32601
+	lspInvalidParams  = -32602
+
+	lspSeverityError = 1
+
+	lspCompletionFunction = 3
+	lspCompletionVariable = 6
+	lspCompletionKeyword  = 14
+)
+
+type lspMessage struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspLocation struct {
+	URI   string   `json:"uri"`
+	Range lspRange `json:"range"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspTextEdit struct {
+	Range   lspRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+type lspCompletionItem struct {
+	Label         string      `json:"label"`
+	Kind          int         `json:"kind"`
+	Detail        string      `json:"detail,omitempty"`
+	Documentation string      `json:"documentation,omitempty"`
+	TextEdit      lspTextEdit `json:"textEdit"`
+}
+
+// lspTextDocumentPosition holds the parameters of requests about
+// a position in a document, like hover and definition requests
+type lspTextDocumentPosition struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+	Position lspPosition `json:"position"`
+}
+
+// languageServer is a Language Server Protocol server for Xin source
+// files. It analyzes each open document statically with analyzeSource
+// whenever it changes, and answers requests from the latest analysis.
+type languageServer struct {
+	out io.Writer
+
+	// globals holds the names bound in a new Vm, and stdlib those
+	// of them that are native forms or bound in the standard library,
+	// with their bindings in the standard library if any
+	globals map[string]bool
+	stdlib  map[string]stdlibEntry
+
+	// docs holds the analyses of the open documents, by URI, and
+	// parsed the latest analyses that parsed. Documents often do not
+	// parse while they are edited, so completions come from the
+	// names bound in the latest version of a document that did.
+	docs   map[string]*sourceAnalysis
+	parsed map[string]*sourceAnalysis
+}
+
+// ServeLanguageServer runs a Language Server Protocol server, reading
+// requests and notifications from r and writing responses and
+// notifications to w, as a language server launched by an editor does
+// over stdio. It returns once the client sends the exit notification,
+// or closes r.
+func ServeLanguageServer(r io.Reader, w io.Writer) error {
+	s, err := newLanguageServer(w)
+	if err != nil {
+		return err
+	}
+
+	reader := textproto.NewReader(bufio.NewReader(r))
+	for {
+		msg, err := readProtocolMessage(reader)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		var m lspMessage
+		if err := json.Unmarshal(msg, &m); err != nil {
+			return fmt.Errorf("invalid message: %s", err)
+		}
+
+		if m.Method == "exit" {
+			return nil
+		}
+		s.handle(m)
+	}
+}
+
+func newLanguageServer(w io.Writer) (*languageServer, error) {
+	globals, stdlib, err := loadStdlibNames()
+	if err != nil {
+		return nil, err
+	}
+
+	return &languageServer{
+		out:     w,
+		globals: globals,
+		stdlib:  stdlib,
+		docs:    make(map[string]*sourceAnalysis),
+		parsed:  make(map[string]*sourceAnalysis),
+	}, nil
+}
+
+func (s *languageServer) send(msg map[string]interface{}) {
+	msg["jsonrpc"] = "2.0"
+	body, _ := json.Marshal(msg)
+	writeProtocolMessage(s.out, body)
+}
+
+func (s *languageServer) respond(id json.RawMessage, result interface{}) {
+	s.send(map[string]interface{}{
+		"id":     id,
+		"result": result,
+	})
+}
+
+func (s *languageServer) fail(id json.RawMessage, code int, message string) {
+	s.send(map[string]interface{}{
+		"id": id,
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+func (s *languageServer) notify(method string, params interface{}) {
+	s.send(map[string]interface{}{
+		"method": method,
+		"params": params,
+	})
+}
+
+// handle responds to a request, or acts on a notification,
+// which has no id
+func (s *languageServer) handle(m lspMessage) {
+	isRequest := len(m.ID) > 0 && string(m.ID) != "null"
+
+	var pos lspTextDocumentPosition
+	switch m.Method {
+	case "textDocument/hover", "textDocument/definition", "textDocument/completion":
+		if err := json.Unmarshal(m.Params, &pos); err != nil {
+			s.fail(m.ID, lspInvalidParams, err.Error())
+			return
+		}
+	}
+
+	switch m.Method {
+	case "initialize":
+		s.respond(m.ID, map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				// documents are synced in full on each change
+				"textDocumentSync":   1,
+				"hoverProvider":      true,
+				"definitionProvider": true,
+				"completionProvider": map[string]interface{}{
+					"triggerCharacters": []string{":"},
+				},
+			},
+			"serverInfo": map[string]interface{}{
+				"name": "xin",
+			},
+		})
+	case "shutdown":
+		s.respond(m.ID, nil)
+	case "textDocument/didOpen":
+		var params struct {
+			TextDocument struct {
+				URI  string `json:"uri"`
+				Text string `json:"text"`
+			} `json:"textDocument"`
+		}
+		if json.Unmarshal(m.Params, &params) == nil {
+			s.update(params.TextDocument.URI, params.TextDocument.Text)
+		}
+	case "textDocument/didChange":
+		var params struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+			ContentChanges []struct {
+				Text string `json:"text"`
+			} `json:"contentChanges"`
+		}
+		if json.Unmarshal(m.Params, &params) == nil && len(params.ContentChanges) > 0 {
+			changes := params.ContentChanges
+			s.update(params.TextDocument.URI, changes[len(changes)-1].Text)
+		}
+	case "textDocument/didClose":
+		var params struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+		}
+		if json.Unmarshal(m.Params, &params) == nil {
+			delete(s.docs, params.TextDocument.URI)
+			delete(s.parsed, params.TextDocument.URI)
+			s.notify("textDocument/publishDiagnostics", map[string]interface{}{
+				"uri":         params.TextDocument.URI,
+				"diagnostics": []lspDiagnostic{},
+			})
+		}
+	case "textDocument/hover":
+		s.respond(m.ID, s.hover(pos))
+	case "textDocument/definition":
+		s.respond(m.ID, s.definition(pos))
+	case "textDocument/completion":
+		s.respond(m.ID, s.completion(pos))
+	default:
+		if isRequest {
+			s.fail(m.ID, lspMethodNotFound, fmt.Sprintf("Unsupported method %s", m.Method))
+		}
+	}
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	return u.Path
+}
+
+func pathToURI(path string) string {
+	u := url.URL{
+		Scheme: "file",
+		Path:   path,
+	}
+	return u.String()
+}
+
+// update analyzes a document after it changes, and
+// publishes the problems found in it as diagnostics
+func (s *languageServer) update(uri, text string) {
+	sa := analyzeSource(uriToPath(uri), text, s.globals)
+	s.docs[uri] = sa
+	if sa.root != nil {
+		s.parsed[uri] = sa
+	}
+
+	diagnostics := []lspDiagnostic{}
+	if sa.err != nil {
+		start := sa.lspPosition(sa.err.pos())
+		end := start
+		end.Character++
+		diagnostics = append(diagnostics, lspDiagnostic{
+			Range: lspRange{
+				Start: start,
+				End:   end,
+			},
+			Severity: lspSeverityError,
+			Source:   "xin",
+			Message:  sa.err.Error(),
+		})
+	}
+	for _, node := range sa.undefined {
+		diagnostics = append(diagnostics, lspDiagnostic{
+			Range:    sa.nameRange(node),
+			Severity: lspSeverityError,
+			Source:   "xin",
+			Message:  UndefinedNameError{name: node.token.value}.Error(),
+		})
+	}
+	for _, imp := range sa.imports {
+		if imp.path != "" && imp.source == nil {
+			diagnostics = append(diagnostics, lspDiagnostic{
+				Range:    sa.formRange(imp.node),
+				Severity: lspSeverityError,
+				Source:   "xin",
+				Message:  fmt.Sprintf("Could not open imported file %s", imp.path),
+			})
+		}
+	}
+
+	s.notify("textDocument/publishDiagnostics", map[string]interface{}{
+		"uri":         uri,
+		"diagnostics": diagnostics,
+	})
+}
+
+// nameAt finds the name at a position in a document. It returns the
+// name node and, if the name is bound in the document or a file it
+// imports, its binding.
+func (s *languageServer) nameAt(pos lspTextDocumentPosition) (*sourceAnalysis, *astNode, *binding) {
+	sa, prs := s.docs[pos.TextDocument.URI]
+	if !prs || sa.root == nil {
+		return nil, nil, nil
+	}
+
+	for _, ref := range sa.references {
+		if sa.nameRange(ref.node).contains(pos.Position) {
+			return sa, ref.node, ref.binding
+		}
+	}
+	for _, b := range sa.bindings {
+		if sa.nameRange(b.node).contains(pos.Position) {
+			return sa, b.node, b
+		}
+	}
+	return sa, nil, nil
+}
+
+func (s *languageServer) definition(pos lspTextDocumentPosition) interface{} {
+	sa, node, b := s.nameAt(pos)
+	if sa == nil {
+		return nil
+	}
+
+	if b != nil {
+		return lspLocation{
+			URI:   pathToURI(b.source.path),
+			Range: b.source.nameRange(b.node),
+		}
+	}
+
+	// the path of an import leads to the imported file
+	if node == nil {
+		for _, imp := range sa.imports {
+			if imp.source != nil && sa.formRange(imp.node.leaves[1]).contains(pos.Position) {
+				return lspLocation{
+					URI: pathToURI(imp.path),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *languageServer) hover(pos lspTextDocumentPosition) interface{} {
+	sa, node, b := s.nameAt(pos)
+	if node == nil {
+		return nil
+	}
+
+	name := node.token.value
+	doc := ""
+	if b != nil {
+		doc = b.describe(name)
+	} else if entry, prs := s.stdlib[name]; prs {
+		doc = entry.describe()
+	} else if s.globals[name] {
+		doc = "```xin\n" + name + "\n```\n\nBuiltin value"
+	} else {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"contents": map[string]interface{}{
+			"kind":  "markdown",
+			"value": doc,
+		},
+		"range": sa.nameRange(node),
+	}
+}
+
+// describe documents a binding for hovers, with its signature
+// and the comment above its definition
+func (b *binding) describe(name string) string {
+	doc := "```xin\n" + b.signature(name) + "\n```"
+	if b.kind == bindParam {
+		return doc + "\n\nParameter of `" + b.signature(b.form.leaves[1].leaves[0].String()) + "`"
+	}
+
+	if comment := b.source.comment(b.form.position.line); comment != "" {
+		doc += "\n\n" + comment
+	}
+	return doc
+}
+
+func (e stdlibEntry) describe() string {
+	if e.binding == nil {
+		return "```xin\n" + e.name + "\n```\n\nNative form"
+	}
+	return e.binding.describe(e.name)
+}
+
+func (s *languageServer) completion(pos lspTextDocumentPosition) interface{} {
+	items := []lspCompletionItem{}
+
+	sa, prs := s.docs[pos.TextDocument.URI]
+	if !prs {
+		return items
+	}
+
+	// the name being completed ends at the cursor
+	line := ""
+	if pos.Position.Line < len(sa.lines) {
+		line = sa.lines[pos.Position.Line]
+	}
+	end := byteColumn(line, pos.Position.Character)
+	start := end
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(line[:start])
+		if unicode.IsSpace(r) || strings.ContainsRune("()';", r) {
+			break
+		}
+		start -= size
+	}
+	prefix := line[start:end]
+	editRange := lspRange{
+		Start: lspPosition{pos.Position.Line, utf16Column(line, start)},
+		End:   pos.Position,
+	}
+
+	seen := make(map[string]bool)
+	add := func(item lspCompletionItem) {
+		if seen[item.Label] || !strings.HasPrefix(item.Label, prefix) {
+			return
+		}
+		seen[item.Label] = true
+		item.TextEdit = lspTextEdit{
+			Range:   editRange,
+			NewText: item.Label,
+		}
+		items = append(items, item)
+	}
+
+	bindingItem := func(name string, b *binding) lspCompletionItem {
+		item := lspCompletionItem{
+			Label:  name,
+			Kind:   lspCompletionVariable,
+			Detail: b.signature(name),
+		}
+		if b.kind == bindForm || b.kind == bindMacro {
+			item.Kind = lspCompletionFunction
+		}
+		if b.kind != bindParam {
+			item.Documentation = b.source.comment(b.form.position.line)
+		}
+		return item
+	}
+
+	if parsed, prs := s.parsed[pos.TextDocument.URI]; prs {
+		for _, b := range parsed.bindings {
+			add(bindingItem(b.name, b))
+		}
+		for name, b := range parsed.imported {
+			add(bindingItem(name, b))
+		}
+	}
+	for name, entry := range s.stdlib {
+		if entry.binding != nil {
+			add(bindingItem(name, entry.binding))
+		} else {
+			add(lspCompletionItem{
+				Label:  name,
+				Kind:   lspCompletionFunction,
+				Detail: "native form",
+			})
+		}
+	}
+	for name := range s.globals {
+		add(lspCompletionItem{
+			Label: name,
+			Kind:  lspCompletionVariable,
+		})
+	}
+	for _, keyword := range []string{"if", "do", "import"} {
+		add(lspCompletionItem{
+			Label: keyword,
+			Kind:  lspCompletionKeyword,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Label < items[j].Label
+	})
+	return items
+}
+
+func (r lspRange) contains(p lspPosition) bool {
+	before := func(a, b lspPosition) bool {
+		return a.Line < b.Line || (a.Line == b.Line && a.Character <= b.Character)
+	}
+	return before(r.Start, p) && before(p, r.End)
+}
+
+func (sa *sourceAnalysis) lspPosition(pos position) lspPosition {
+	line, col := sa.sourceOffset(pos)
+	return lspPosition{line, utf16Column(sa.lines[line], col)}
+}
+
+// nameRange finds the range of a name in the source
+func (sa *sourceAnalysis) nameRange(node *astNode) lspRange {
+	line, start, end := sa.nameOffset(node)
+	text := sa.lines[line]
+
+	return lspRange{
+		Start: lspPosition{line, utf16Column(text, start)},
+		End:   lspPosition{line, utf16Column(text, end)},
+	}
+}
+
+// formRange finds the range of the first line of a form, or
+// of a string literal, which is positioned at its closing quote.
+func (sa *sourceAnalysis) formRange(node *astNode) lspRange {
+	line, col := sa.sourceOffset(node.position)
+	text := sa.lines[line]
+
+	if !node.isForm && node.token.kind == tkStringLiteral {
+		start := strings.LastIndex(text[:col], "'")
+		if start < 0 {
+			start = 0
+		}
+		return lspRange{
+			Start: lspPosition{line, utf16Column(text, start)},
+			End:   lspPosition{line, utf16Column(text, col+1)},
+		}
+	}
+
+	return lspRange{
+		Start: lspPosition{line, utf16Column(text, col)},
+		End:   lspPosition{line, utf16Column(text, len(text))},
+	}
+}
+
+// utf16Column converts a byte column in a line to the
+// UTF-16 code units the Language Server Protocol counts
+func utf16Column(line string, col int) int {
+	if col > len(line) {
+		col = len(line)
+	}
+
+	units := 0
+	for _, r := range line[:col] {
+		units += len(utf16.Encode([]rune{r}))
+	}
+	return units
+}
+
+// byteColumn is the inverse of utf16Column
+func byteColumn(line string, units int) int {
+	for i, r := range line {
+		if units <= 0 {
+			return i
+		}
+		units -= len(utf16.Encode([]rune{r}))
+	}
+	return len(line)
+}
@@ -0,0 +1,399 @@
+package xin
+
+// AstValue is a first-class representation of a piece of Xin syntax.
+// Macros receive their arguments as AstValues, and return an AstValue
+// that is evaluated in place of the macro invocation.
+type AstValue struct {
+	node *astNode
+}
+
+func (v AstValue) String() string {
+	return "(<ast> " + v.node.String() + ")"
+}
+
+func (v AstValue) Repr() string {
+	return v.String()
+}
+
+func (v AstValue) Equal(o Value) bool {
+	if ov, ok := o.(AstValue); ok {
+		return v.node == ov.node
+	}
+
+	return false
+}
+
+// MacroFormValue is a form defined with the macro bind form (::),
+// which receives its arguments unevaluated, as AstValues.
+type MacroFormValue struct {
+	frame      *Frame
+	name       string
+	scope      *scopeInfo
+	arguments  *argList
+	definition *astNode
+}
+
+func (v MacroFormValue) String() string {
+	return "(<macro>" + v.arguments.String() + ") " + v.definition.String()
+}
+
+func (v MacroFormValue) Repr() string {
+	return v.String()
+}
+
+func (v MacroFormValue) Equal(o Value) bool {
+	if ov, ok := o.(MacroFormValue); ok {
+		return v.definition == ov.definition
+	}
+
+	return false
+}
+
+func evalMacroBindForm(fr *Frame, args []*astNode) (Value, InterpreterError) {
+	if len(args) != 2 {
+		return nil, InvalidBindError{nodes: args}
+	}
+
+	specimen, body := args[0], args[1]
+	if !specimen.isForm || len(specimen.leaves) < 1 {
+		return nil, InvalidBindError{
+			nodes:    args,
+			position: specimen.position,
+		}
+	}
+
+	macroNameNode := specimen.leaves[0]
+	if macroNameNode.isForm || macroNameNode.token.kind != tkName {
+		return nil, InvalidBindError{
+			nodes:    args,
+			position: macroNameNode.position,
+		}
+	}
+
+	params, errNode := parseArgList(specimen.leaves[1:])
+	if errNode != nil {
+		return nil, InvalidBindError{
+			nodes:    args,
+			position: errNode.position,
+		}
+	}
+
+	macro := MacroFormValue{
+		frame:      fr,
+		name:       macroNameNode.token.value,
+		scope:      specimen.scope,
+		arguments:  params,
+		definition: body,
+	}
+	fr.putName(macroNameNode, macro)
+
+	return macro, nil
+}
+
+// expandMacro invokes a macro with the unevaluated arguments of the
+// given form node, and returns the syntax tree it expands to.
+func expandMacro(fr *Frame, node *astNode, macro MacroFormValue) (*astNode, InterpreterError) {
+	localFrame := newScopedFrame(macro.frame, macro.scope)
+	localFrame.Vm = fr.Vm
+
+	argNodes := node.leaves[1:]
+	args := make([]Value, len(argNodes))
+	for i, n := range argNodes {
+		args[i] = AstValue{node: n}
+	}
+	err := bindArgs(localFrame, macro.arguments, macro.scope, args, macro.name, node, false)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := unlazy(LazyValue{
+		frame:    localFrame,
+		node:     macro.definition,
+		name:     macro.name,
+		callsite: node,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	expanded, err := valueToAstNode(result, node)
+	if err != nil {
+		return nil, err
+	}
+
+	// the expansion may place syntax from the macro's arguments into new
+	// scopes, so we resolve names in a fresh copy of it, in the scope of
+	// the frame where it will be evaluated.
+	expanded = copyAstNode(expanded)
+	resolveNames(expanded, fr.scope)
+
+	// syntax synthesized by the macro has no source position of its own,
+	// so we attribute it to the macro call site for error reporting
+	positionSynthesizedNodes(expanded, node.position)
+
+	return expanded, nil
+}
+
+func evalMacroForm(fr *Frame, node *astNode, macro MacroFormValue) (Value, InterpreterError) {
+	expanded, err := expandMacro(fr, node, macro)
+	if err != nil {
+		return nil, err
+	}
+
+	return eval(fr, expanded)
+}
+
+func positionSynthesizedNodes(node *astNode, pos position) {
+	if node.position.path == "" {
+		node.position = pos
+		node.token.position = pos
+	}
+
+	for _, leaf := range node.leaves {
+		positionSynthesizedNodes(leaf, pos)
+	}
+}
+
+// valueToAstNode converts a Xin value into syntax. AstValues are used
+// as-is, ints, fracs, and strings become literals, and any other value
+// is embedded into syntax that evaluates to it.
+func valueToAstNode(v Value, node *astNode) (*astNode, InterpreterError) {
+	switch val := v.(type) {
+	case AstValue:
+		return val.node, nil
+	case IntValue:
+		return &astNode{
+			token: token{
+				kind:  tkNumberLiteralInt,
+				value: val.String(),
+				intv:  val,
+			},
+		}, nil
+	case FracValue:
+		return &astNode{
+			token: token{
+				kind:  tkNumberLiteralDecimal,
+				value: val.String(),
+				fracv: val,
+			},
+		}, nil
+	case StringValue:
+		return &astNode{
+			token: token{
+				kind:  tkStringLiteral,
+				value: string(val),
+			},
+		}, nil
+	}
+
+	return valueLiteralNode(v), nil
+}
+
+func valueLiteralNode(v Value) *astNode {
+	return &astNode{
+		token: token{
+			kind:    tkValueLiteral,
+			literal: v,
+		},
+	}
+}
+
+func astForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	n, err := valueToAstNode(args[0], node)
+	if err != nil {
+		return nil, err
+	}
+
+	return AstValue{node: n}, nil
+}
+
+// astLitForm wraps any value, including an AstValue,
+// in syntax that evaluates to that value.
+func astLitForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	return AstValue{node: valueLiteralNode(args[0])}, nil
+}
+
+func astKindForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	first := args[0]
+
+	if firstAst, ok := first.(AstValue); ok {
+		n := firstAst.node
+		if n.isForm {
+			return StringValue("form"), nil
+		}
+
+		switch n.token.kind {
+		case tkName:
+			return StringValue("name"), nil
+		case tkNumberLiteralInt, tkNumberLiteralHex:
+			return StringValue("int"), nil
+		case tkNumberLiteralDecimal:
+			return StringValue("frac"), nil
+		case tkStringLiteral:
+			return StringValue("str"), nil
+		case tkValueLiteral:
+			return StringValue("value"), nil
+		default:
+			return StringValue("special"), nil
+		}
+	}
+
+	return nil, MismatchedArgumentsError{
+		node: node,
+		args: args,
+	}
+}
+
+func astValueForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	first := args[0]
+
+	if firstAst, ok := first.(AstValue); ok {
+		n := firstAst.node
+		if n.isForm {
+			return astLeavesForm(fr, args, node)
+		}
+
+		switch n.token.kind {
+		case tkNumberLiteralInt, tkNumberLiteralHex:
+			return n.token.intv, nil
+		case tkNumberLiteralDecimal:
+			return n.token.fracv, nil
+		case tkStringLiteral:
+			return StringValue(n.token.value), nil
+		case tkValueLiteral:
+			return n.token.literal, nil
+		default:
+			return StringValue(n.token.String()), nil
+		}
+	}
+
+	return nil, MismatchedArgumentsError{
+		node: node,
+		args: args,
+	}
+}
+
+func astLeavesForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	first := args[0]
+
+	if firstAst, ok := first.(AstValue); ok {
+		leaves := make([]Value, len(firstAst.node.leaves))
+		for i, leaf := range firstAst.node.leaves {
+			leaves[i] = AstValue{node: leaf}
+		}
+		return NewVecValue(leaves), nil
+	}
+
+	return nil, MismatchedArgumentsError{
+		node: node,
+		args: args,
+	}
+}
+
+func astFormForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	first := args[0]
+
+	if firstVec, ok := first.(VecValue); ok {
+		items := firstVec.underlying.items
+		if len(items) == 0 {
+			return nil, InvalidFormError{
+				position: node.position,
+			}
+		}
+
+		leaves := make([]*astNode, len(items))
+		for i, item := range items {
+			leaf, err := valueToAstNode(item, node)
+			if err != nil {
+				return nil, err
+			}
+			leaves[i] = leaf
+		}
+
+		return AstValue{
+			node: &astNode{
+				isForm: true,
+				leaves: leaves,
+			},
+		}, nil
+	}
+
+	return nil, MismatchedArgumentsError{
+		node: node,
+		args: args,
+	}
+}
+
+func astNameForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	first := args[0]
+
+	if firstStr, ok := first.(StringValue); ok && len(firstStr) > 0 {
+		tok := bufToToken(string(firstStr), position{})
+		return AstValue{
+			node: &astNode{
+				token: tok,
+			},
+		}, nil
+	}
+
+	return nil, MismatchedArgumentsError{
+		node: node,
+		args: args,
+	}
+}
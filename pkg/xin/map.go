@@ -37,11 +37,36 @@ func (v hashableNativeFormProxy) Equal(ov Value) bool {
 	panic("hashableNativeFormProxy should not be equality-checked")
 }
 
+// hashableNativeFormRef is a hashable proxy for NativeFormValues
+// created at runtime, which are not bound to their names in the Vm
+type hashableNativeFormRef struct {
+	name string
+	ref  *formEvaler
+}
+
+func (v hashableNativeFormRef) String() string {
+	return "(<native form> " + v.name + ")"
+}
+
+func (v hashableNativeFormRef) Repr() string {
+	return v.String()
+}
+
+func (v hashableNativeFormRef) Equal(ov Value) bool {
+	panic("hashableNativeFormRef should not be equality-checked")
+}
+
 func hashable(v Value) Value {
 	switch val := v.(type) {
 	case StringValue:
 		return hashableStringProxy(val)
 	case NativeFormValue:
+		if val.ref != nil {
+			return hashableNativeFormRef{
+				name: val.name,
+				ref:  val.ref,
+			}
+		}
 		return hashableNativeFormProxy(val.name)
 	default:
 		return v
@@ -58,6 +83,12 @@ func dehash(vm *Vm, v Value) Value {
 			name:   string(val),
 			evaler: vm.evalers[string(val)],
 		}
+	case hashableNativeFormRef:
+		return NativeFormValue{
+			name:   val.name,
+			evaler: *val.ref,
+			ref:    val.ref,
+		}
 	default:
 		return v
 	}
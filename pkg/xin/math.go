@@ -2,11 +2,10 @@ package xin
 
 import (
 	"math"
-	"math/rand"
 )
 
 func mathRandForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
-	return FracValue(rand.Float64()), nil
+	return FracValue(fr.Vm.rand.Float64()), nil
 }
 
 func mathSinForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
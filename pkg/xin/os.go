@@ -6,21 +6,25 @@ import (
 	"io"
 	"net"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
 const readBufferSize = 4096
 
+// osWaitForm calls a callback after a delay in seconds. Without
+// a callback, it returns a future that resolves after the delay.
 func osWaitForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
-	if len(args) < 2 {
+	if len(args) < 1 {
 		return nil, IncorrectNumberOfArgsError{
 			node:     node,
-			required: 2,
+			required: 1,
 			given:    len(args),
 		}
 	}
 
-	first, second := args[0], args[1]
+	first := args[0]
 
 	var duration float64
 	if firstInt, fok := first.(IntValue); fok {
@@ -35,39 +39,88 @@ func osWaitForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError
 	}
 
 	vm := fr.Vm
-	vm.waiter.Add(1)
-	go func() {
-		defer vm.waiter.Done()
+	ctx := vm.ctx
+	op := vm.loop.start(ctx, "os::wait", node)
 
-		time.Sleep(time.Duration(
+	var fut FutureValue
+	var rv Value = trueValue
+	if len(args) < 2 {
+		fut = newFutureValue(vm)
+		rv = fut
+	}
+
+	go func() {
+		timer := time.NewTimer(time.Duration(
 			int64(duration * float64(time.Second)),
 		))
+		defer timer.Stop()
 
-		vm.Lock()
-		defer vm.Unlock()
-
-		_, err := unlazyEvalFormValue(fr, &astNode{
-			// dummy function invocation astNode
-			// to help generate proper error messages
-			isForm: true,
-			leaves: []*astNode{
-				&astNode{
-					isForm: false,
-				},
-			},
-		}, second)
-		if err != nil {
-			fmt.Println("Eval error in os::wait:", FormatError(err))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			if len(args) < 2 {
+				fut.fail(interruptedErrorValue(node))
+			}
+			op.complete(nil)
+			return
+		}
+
+		if len(args) < 2 {
+			fut.resolve(trueValue)
+			op.complete(nil)
 			return
 		}
+
+		op.complete(func() InterpreterError {
+			_, err := unlazyEvalCallback(fr, args[1], []Value{}, node)
+			return err
+		})
 	}()
 
-	return trueValue, nil
+	return rv, nil
+}
+
+func newRWStream(vm *Vm, rw io.ReadWriteCloser) StreamValue {
+	return newBufferedRWStream(vm, rw, bufio.NewReader(rw))
 }
 
-func newRWStream(rw io.ReadWriteCloser) StreamValue {
-	rwStream := NewStream()
-	reader := bufio.NewReader(rw)
+// newFileStream returns a stream for a file opened with flag, which is
+// a source if the file was opened to read and a sink if it was opened to
+// write. os::seek and os::tell can move the stream if it is a regular
+// file. Pipes, terminals, and other special files cannot seek, and
+// neither can their streams.
+func newFileStream(vm *Vm, file *os.File, flag int) StreamValue {
+	reader := bufio.NewReader(file)
+	fileStream := newBufferedRWStream(vm, file, reader)
+
+	switch flag & (os.O_RDONLY | os.O_WRONLY | os.O_RDWR) {
+	case os.O_RDONLY:
+		fileStream.callbacks.sink = nil
+	case os.O_WRONLY:
+		fileStream.callbacks.source = nil
+	}
+
+	fileStat, err := file.Stat()
+	if err != nil || !fileStat.Mode().IsRegular() {
+		return fileStream
+	}
+
+	fileStream.callbacks.seeker = func(offset int64, whence int) (int64, error) {
+		// the read buffer is ahead of what the
+		// program has read from the stream
+		if whence == io.SeekCurrent {
+			offset -= int64(reader.Buffered())
+		}
+		pos, err := file.Seek(offset, whence)
+		reader.Reset(file)
+		return pos, err
+	}
+
+	return fileStream
+}
+
+func newBufferedRWStream(vm *Vm, rw io.ReadWriteCloser, reader *bufio.Reader) StreamValue {
+	rwStream := vm.NewStream()
 	closed := false
 
 	rwStream.callbacks.source = func() (Value, InterpreterError) {
@@ -77,8 +130,13 @@ func newRWStream(rw io.ReadWriteCloser) StreamValue {
 
 		buffer := make([]byte, readBufferSize)
 		readBytes, err := reader.Read(buffer)
-		if err != nil {
+		if err == io.EOF {
 			return zeroValue, nil
+		} else if err != nil {
+			return ErrorValue{
+				message: err.Error(),
+				kind:    "os",
+			}, nil
 		}
 
 		return StringValue(buffer[:readBytes]), nil
@@ -106,7 +164,7 @@ func newRWStream(rw io.ReadWriteCloser) StreamValue {
 		}
 	}
 
-	rwStream.callbacks.closer = func() InterpreterError {
+	rwStream.callbacks.closer = func(_ *Vm) InterpreterError {
 		if !closed {
 			closed = true
 			rw.Close()
@@ -117,6 +175,22 @@ func newRWStream(rw io.ReadWriteCloser) StreamValue {
 	return rwStream
 }
 
+// statMap describes a file as returned by os::stat.
+func statMap(fileStat os.FileInfo) MapValue {
+	statMap := NewMapValue()
+
+	if fileStat.IsDir() {
+		statMap.set(StringValue("dir"), trueValue)
+	} else {
+		statMap.set(StringValue("dir"), falseValue)
+	}
+	statMap.set(StringValue("name"), StringValue(fileStat.Name()))
+	statMap.set(StringValue("size"), IntValue(fileStat.Size()))
+	statMap.set(StringValue("mod"), IntValue(fileStat.ModTime().Unix()))
+
+	return statMap
+}
+
 func osStatForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
 	if len(args) < 1 {
 		return nil, IncorrectNumberOfArgsError{
@@ -131,21 +205,10 @@ func osStatForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError
 	if firstStr, ok := first.(StringValue); ok {
 		fileStat, err := os.Stat(string(firstStr))
 		if err != nil {
-			return zeroValue, nil
+			return osErrorValue(err, node), nil
 		}
 
-		statMap := NewMapValue()
-
-		if fileStat.IsDir() {
-			statMap.set(StringValue("dir"), trueValue)
-		} else {
-			statMap.set(StringValue("dir"), falseValue)
-		}
-		statMap.set(StringValue("name"), StringValue(fileStat.Name()))
-		statMap.set(StringValue("size"), IntValue(fileStat.Size()))
-		statMap.set(StringValue("mod"), IntValue(fileStat.ModTime().Unix()))
-
-		return statMap, nil
+		return statMap(fileStat), nil
 	}
 
 	return nil, MismatchedArgumentsError{
@@ -154,6 +217,22 @@ func osStatForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError
 	}
 }
 
+// openFlags are the flags to os.OpenFile for each mode of os::open
+var openFlags = map[string]int{
+	"r":  os.O_RDONLY,
+	"w":  os.O_WRONLY | os.O_CREATE | os.O_TRUNC,
+	"a":  os.O_WRONLY | os.O_CREATE | os.O_APPEND,
+	"rw": os.O_RDWR | os.O_CREATE,
+	"x":  os.O_WRONLY | os.O_CREATE | os.O_EXCL,
+}
+
+// osOpenForm opens a file as a stream, in a mode: 'r' to read, 'w' to
+// write from the start of the file, truncating it, 'a' to append to the
+// file, 'rw' to read and write, or 'x' to write a new file, failing if
+// it already exists. Every mode but 'r' creates the file if it does not
+// exist, with the given permission bits, or 0644. The default mode is
+// 'r', so opening a missing file without a mode does not create it. If
+// the file cannot be opened, it returns an error value.
 func osOpenForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
 	if len(args) < 1 {
 		return nil, IncorrectNumberOfArgsError{
@@ -163,25 +242,96 @@ func osOpenForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError
 		}
 	}
 
-	first := args[0]
+	firstStr, ok := args[0].(StringValue)
+	if !ok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
 
-	if firstStr, ok := first.(StringValue); ok {
-		flag := os.O_CREATE | os.O_RDWR
-		file, err := os.OpenFile(string(firstStr), flag, 0644)
-		if err != nil {
-			return zeroValue, nil
+	flag := openFlags["r"]
+	if len(args) >= 2 {
+		secondStr, sok := args[1].(StringValue)
+		modeFlag, mok := openFlags[string(secondStr)]
+		if !sok || !mok {
+			return nil, MismatchedArgumentsError{
+				node: node,
+				args: args,
+			}
 		}
+		flag = modeFlag
+	}
 
-		return newRWStream(file), nil
+	var perm os.FileMode = 0644
+	if len(args) >= 3 {
+		thirdInt, ok := args[2].(IntValue)
+		if !ok {
+			return nil, MismatchedArgumentsError{
+				node: node,
+				args: args,
+			}
+		}
+		perm = os.FileMode(thirdInt) & os.ModePerm
 	}
 
-	return nil, MismatchedArgumentsError{
-		node: node,
-		args: args,
+	file, err := os.OpenFile(string(firstStr), flag, perm)
+	if err != nil {
+		return osErrorValue(err, node), nil
 	}
+
+	return newFileStream(fr.Vm, file, flag), nil
 }
 
-func osDeleteForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+// seekWhence are the positions os::seek can seek relative to
+var seekWhence = map[string]int{
+	"start":   io.SeekStart,
+	"current": io.SeekCurrent,
+	"end":     io.SeekEnd,
+}
+
+// osSeekForm moves the offset of a file stream, after any reads and
+// writes already made on the stream. The offset is relative to the start
+// of the file, or to 'current' or 'end' if given. It calls a callback
+// with the new offset, or an error value if it fails, and without a
+// callback, returns a future of the new offset.
+func osSeekForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 2 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 2,
+			given:    len(args),
+		}
+	}
+
+	firstStream, fok := args[0].(StreamValue)
+	secondInt, sok := args[1].(IntValue)
+	whence := io.SeekStart
+	callbackIndex := 2
+	if len(args) >= 3 {
+		if thirdStr, ok := args[2].(StringValue); ok {
+			whence, ok = seekWhence[string(thirdStr)]
+			if !ok {
+				sok = false
+			}
+			callbackIndex = 3
+		}
+	}
+	callback, cok := callbackArg(args, callbackIndex)
+	if !fok || !sok || !cok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	return seekStream(fr, "os::seek", node, firstStream, int64(secondInt), whence, callback)
+}
+
+// osTellForm calls a callback with the offset of a file stream, after any
+// reads and writes already made on the stream, or an error value if it
+// fails. Without a callback, it returns a future of the offset.
+func osTellForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
 	if len(args) < 1 {
 		return nil, IncorrectNumberOfArgsError{
 			node:     node,
@@ -190,51 +340,139 @@ func osDeleteForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterErr
 		}
 	}
 
-	first := args[0]
-	var second Value
-	if len(args) >= 2 {
-		second = args[1]
-	} else {
-		second = Noop
+	firstStream, fok := args[0].(StreamValue)
+	callback, cok := callbackArg(args, 1)
+	if !fok || !cok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
 	}
 
-	var secondForm Value
-	firstStr, fok := first.(StringValue)
-	secondForm, sok := second.(FormValue)
-	if !sok {
-		secondForm, sok = second.(NativeFormValue)
+	return seekStream(fr, "os::tell", node, firstStream, 0, io.SeekCurrent, callback)
+}
+
+// seekStream seeks a stream in order with the other operations on
+// it, for os::seek and os::tell.
+func seekStream(fr *Frame, name string, node *astNode, stream StreamValue, offset int64, whence int, callback Value) (Value, InterpreterError) {
+	if !stream.isSeek() {
+		return nil, InvalidStreamCallbackError{
+			reason: "Cannot seek a stream that is not a file",
+		}
 	}
 
-	if fok && sok {
-		vm := fr.Vm
-		vm.waiter.Add(1)
-		go func() {
-			defer vm.waiter.Done()
+	vm := fr.Vm
+	op := vm.loop.start(vm.ctx, name, node)
 
-			err := os.RemoveAll(string(firstStr))
-			rv := falseValue
-			if err != nil {
-				rv = trueValue
-			}
+	var fut FutureValue
+	var result Value = zeroValue
+	if callback == nil {
+		fut = newFutureValue(vm)
+		result = fut
+	}
 
-			vm.Lock()
-			defer vm.Unlock()
+	vm.loop.goStream(stream, func() {
+		var rv Value
+		pos, err := stream.callbacks.seeker(offset, whence)
+		if err != nil {
+			rv = osErrorValue(err, node)
+		} else {
+			rv = IntValue(pos)
+		}
 
-			_, intErr := unlazyEvalFormWithArgs(fr, secondForm, []Value{rv}, node)
-			if intErr != nil {
-				fmt.Println(FormatError(intErr))
-			}
-		}()
+		if callback == nil {
+			fut.settleWith(rv, nil)
+			op.complete(nil)
+			return
+		}
 
-		return zeroValue, nil
+		op.complete(func() InterpreterError {
+			_, err := unlazyEvalCallback(fr, callback, []Value{rv}, node)
+			return err
+		})
+	})
+
+	return result, nil
+}
+
+// callbackArg returns the optional callback of an async native at
+// args[i], or nil if none is given. It reports false if args[i] is
+// given but is not a form.
+func callbackArg(args []Value, i int) (Value, bool) {
+	if len(args) <= i {
+		return nil, true
 	}
 
-	return nil, MismatchedArgumentsError{
-		node: node,
-		args: args,
+	switch args[i].(type) {
+	case FormValue, NativeFormValue:
+		return args[i], true
+	default:
+		return nil, false
 	}
 }
 
+// goFs runs work, which may block on the file system, off the event
+// loop for the async native name. It calls callback with the result of
+// work, or without a callback, returns a future that resolves to the
+// result, or fails if the result is an error value.
+func goFs(fr *Frame, name string, node *astNode, callback Value, work func() Value) Value {
+	vm := fr.Vm
+	op := vm.loop.start(vm.ctx, name, node)
+
+	var fut FutureValue
+	var result Value = zeroValue
+	if callback == nil {
+		fut = newFutureValue(vm)
+		result = fut
+	}
+
+	go func() {
+		rv := work()
+
+		if callback == nil {
+			fut.settleWith(rv, nil)
+			op.complete(nil)
+			return
+		}
+
+		op.complete(func() InterpreterError {
+			_, err := unlazyEvalCallback(fr, callback, []Value{rv}, node)
+			return err
+		})
+	}()
+
+	return result
+}
+
+// osDeleteForm deletes a file or directory, and calls a callback with
+// an error value if it fails, or 0 otherwise. Without a callback, it
+// returns a future that resolves to 0, or fails with the error.
+func osDeleteForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	firstStr, fok := args[0].(StringValue)
+	callback, cok := callbackArg(args, 1)
+	if !fok || !cok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	return goFs(fr, "os::delete", node, callback, func() Value {
+		if err := os.RemoveAll(string(firstStr)); err != nil {
+			return osErrorValue(err, node)
+		}
+		return zeroValue
+	}), nil
+}
+
 func validateNetworkArgs(args []Value, node *astNode) (string, string, InterpreterError) {
 	first, second := args[0], args[1]
 
@@ -277,13 +515,10 @@ func osDialForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError
 
 	conn, netErr := net.Dial(network, addr)
 	if netErr != nil {
-		return nil, NetworkError{
-			reason:   netErr.Error(),
-			position: node.position,
-		}
+		return networkErrorValue(netErr, node), nil
 	}
 
-	return newRWStream(conn), nil
+	return newRWStream(fr.Vm, conn), nil
 }
 
 func osListenForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
@@ -305,16 +540,33 @@ func osListenForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterErr
 
 	listener, netErr := net.Listen(network, addr)
 	if netErr != nil {
-		return nil, NetworkError{
-			reason:   netErr.Error(),
-			position: node.position,
+		return networkErrorValue(netErr, node), nil
+	}
+
+	closeListener := func() {
+		select {
+		case signal <- true:
+			listener.Close()
+		default:
+			// already closed
 		}
 	}
 
 	vm := fr.Vm
-	vm.waiter.Add(1)
+	ctx := vm.ctx
+	op := vm.loop.start(ctx, "os::listen", node)
 	go func(l net.Listener) {
-		defer vm.waiter.Done()
+		defer op.complete(nil)
+
+		stopped := make(chan bool)
+		defer close(stopped)
+		go func() {
+			select {
+			case <-ctx.Done():
+				closeListener()
+			case <-stopped:
+			}
+		}()
 
 		for {
 			conn, err := l.Accept()
@@ -323,30 +575,32 @@ func osListenForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterErr
 				case <-signal:
 					return
 				default:
-					fmt.Println(err.Error())
+					vm.loop.start(ctx, "os::listen", node).complete(func() InterpreterError {
+						return NetworkError{
+							reason:   err.Error(),
+							position: node.position,
+						}
+					})
+					continue
 				}
 			}
 
-			go func(c net.Conn) {
-				vm.Lock()
-				defer vm.Unlock()
+			if ctx.Err() != nil {
+				conn.Close()
+				continue
+			}
 
-				_, err := unlazyEvalFormWithArgs(fr, handler, []Value{newRWStream(c)}, node)
-				if err != nil {
-					fmt.Println(FormatError(err))
-				}
-			}(conn)
+			vm.loop.start(ctx, "os::listen", node).complete(func() InterpreterError {
+				_, err := unlazyEvalCallback(fr, handler, []Value{newRWStream(vm, conn)}, node)
+				return err
+			})
 		}
 	}(listener)
 
-	return NativeFormValue{
-		name: "os::listen::close",
-		evaler: func(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
-			signal <- true
-			listener.Close()
-			return trueValue, nil
-		},
-	}, nil
+	return newNativeForm("os::listen::close", func(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+		closeListener()
+		return trueValue, nil
+	}), nil
 }
 
 func osLogForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
@@ -359,25 +613,176 @@ func osLogForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError)
 	}
 
 	first := args[0]
-	fmt.Println(first.String())
+	fmt.Fprintln(fr.Vm.stdout, first.String())
 
 	return first, nil
 }
 
 func osArgsForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
-	argsVec := make([]Value, len(os.Args))
-	for i, a := range os.Args {
+	argsVec := make([]Value, len(fr.Vm.args))
+	for i, a := range fr.Vm.args {
 		argsVec[i] = StringValue(a)
 	}
 	return NewVecValue(argsVec), nil
 }
 
+// osExitForm ends the program with an exit status, or 0 if none is
+// given, once its pending writes to os::stdout are done. Any async
+// callbacks still pending in the program are dropped.
+func osExitForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	code := 0
+	if len(args) >= 1 {
+		firstInt, ok := args[0].(IntValue)
+		if !ok {
+			return nil, MismatchedArgumentsError{
+				node: node,
+				args: args,
+			}
+		}
+		code = int(firstInt)
+	}
+
+	vm := fr.Vm
+	vm.exit.exitWith(code, node.position)
+	atomic.StoreInt32(vm.interrupted, 1)
+	vm.block(func() {
+		vm.loop.flush(vm.stdoutStream)
+	})
+
+	return nil, ExitError{
+		Code:     code,
+		position: node.position,
+	}
+}
+
 func osTimeForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
 	return FracValue(
 		float64(time.Now().UnixNano()) / 1e9,
 	), nil
 }
 
+// osEnvForm returns a map of the environment variables of the process.
+func osEnvForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	envMap := NewMapValue()
+	for _, entry := range os.Environ() {
+		if i := strings.IndexByte(entry, '='); i >= 0 {
+			envMap.set(StringValue(entry[:i]), StringValue(entry[i+1:]))
+		}
+	}
+	return envMap, nil
+}
+
+// osEnvGetForm returns the value of an environment variable,
+// or 0 if it is not set.
+func osEnvGetForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	if firstStr, ok := args[0].(StringValue); ok {
+		if val, prs := os.LookupEnv(string(firstStr)); prs {
+			return StringValue(val), nil
+		}
+		return zeroValue, nil
+	}
+
+	return nil, MismatchedArgumentsError{
+		node: node,
+		args: args,
+	}
+}
+
+// osEnvSetForm sets an environment variable of the process, which is
+// inherited by processes it starts, and returns the value it set.
+func osEnvSetForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 2 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 2,
+			given:    len(args),
+		}
+	}
+
+	firstStr, fok := args[0].(StringValue)
+	secondStr, sok := args[1].(StringValue)
+	if !fok || !sok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	if err := os.Setenv(string(firstStr), string(secondStr)); err != nil {
+		return osErrorValue(err, node), nil
+	}
+	return secondStr, nil
+}
+
+// osCwdForm returns the working directory of the process, which
+// relative paths given to os::open and os::stat resolve from.
+func osCwdForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return osErrorValue(err, node), nil
+	}
+	return StringValue(cwd), nil
+}
+
+// osChdirForm changes the working directory of the process, and returns
+// the new working directory. Relative imports and os::exec calls in
+// every file the Vm has run resolve from the new working directory
+// afterwards, like relative paths given to os::open.
+func osChdirForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	firstStr, ok := args[0].(StringValue)
+	if !ok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	if err := os.Chdir(string(firstStr)); err != nil {
+		return osErrorValue(err, node), nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return osErrorValue(err, node), nil
+	}
+	for _, fileCwd := range fr.Vm.cwds {
+		*fileCwd = cwd
+	}
+
+	return StringValue(cwd), nil
+}
+
+// osPidForm returns the ID of the process.
+func osPidForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	return IntValue(os.Getpid()), nil
+}
+
+// osHostnameForm returns the name of the host the process runs on,
+// or an error value if it cannot be found.
+func osHostnameForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return osErrorValue(err, node), nil
+	}
+	return StringValue(hostname), nil
+}
+
 func debugDumpForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
 	return StringValue(fr.String()), nil
 }
@@ -2,6 +2,7 @@ package xin
 
 import (
 	"strings"
+	"unsafe"
 )
 
 type astNode struct {
@@ -9,6 +10,21 @@ type astNode struct {
 	token  token
 	leaves []*astNode
 	position
+
+	// lexical address of a name, filled in by resolveNames.
+	// Names that are not scoped are looked up by name at runtime.
+	scoped bool
+	depth  int
+	slot   int
+
+	// scope of the body of a form definition, if this node
+	// is the specimen (f args...) of a definition
+	scope *scopeInfo
+
+	// bytecode for this form (a *chunk), compiled on first evaluation
+	// by the bytecode backend. Syntax trees are shared by tasks running
+	// in parallel, so this is loaded and stored atomically.
+	chunk unsafe.Pointer
 }
 
 func (n astNode) String() string {
@@ -0,0 +1,392 @@
+package xin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sort"
+	"time"
+)
+
+// profiler records the time spent in each invocation of a form, in a
+// tree of the call stacks that forms were invoked from. Like the rest
+// of evaluation, it is only used with the Vm locked.
+type profiler struct {
+	started time.Time
+	root    *profNode
+	// cur is the invocation that is running
+	cur *profNode
+}
+
+// profFunc identifies a form in a profile. Forms defined in Xin are
+// identified by their definitions, and native forms by their names.
+type profFunc struct {
+	name       string
+	definition *astNode
+}
+
+type profNode struct {
+	fn       profFunc
+	parent   *profNode
+	children map[profFunc]*profNode
+	calls    int64
+	// total time spent in invocations of fn from this call stack
+	total time.Duration
+}
+
+func newProfiler() *profiler {
+	root := &profNode{
+		children: make(map[profFunc]*profNode),
+	}
+	return &profiler{
+		started: time.Now(),
+		root:    root,
+		cur:     root,
+	}
+}
+
+// enter records the start of an invocation of fn.
+func (p *profiler) enter(fn profFunc) (*profNode, time.Time) {
+	n, prs := p.cur.children[fn]
+	if !prs {
+		n = &profNode{
+			fn:       fn,
+			parent:   p.cur,
+			children: make(map[profFunc]*profNode),
+		}
+		p.cur.children[fn] = n
+	}
+	p.cur = n
+
+	return n, time.Now()
+}
+
+// exit records the end of an invocation started by enter.
+func (p *profiler) exit(n *profNode, start time.Time) {
+	n.calls++
+	n.total += time.Since(start)
+	p.cur = n.parent
+}
+
+// suspend detaches the profiler from the running invocation while
+// the Vm is unlocked, so that callbacks that run in the meantime are
+// not recorded as invoked by it. resume reattaches it.
+func (p *profiler) suspend() *profNode {
+	cur := p.cur
+	p.cur = p.root
+	return cur
+}
+
+func (p *profiler) resume(cur *profNode) {
+	p.cur = cur
+}
+
+func (n *profNode) self() time.Duration {
+	self := n.total
+	for _, child := range n.children {
+		self -= child.total
+	}
+	if self < 0 {
+		return 0
+	}
+	return self
+}
+
+// Profile is a record of the forms invoked while a Vm was profiled,
+// and of the time spent in each of them.
+type Profile struct {
+	root     *profNode
+	started  time.Time
+	duration time.Duration
+}
+
+// FormProfile summarizes the invocations of one form in a Profile.
+type FormProfile struct {
+	Name string
+	// Position is the source position of the form's definition,
+	// or empty for native forms
+	Position string
+	Calls    int64
+	// Total is the time spent in the form and in the forms it invoked,
+	// and Self the time spent in the form itself
+	Total time.Duration
+	Self  time.Duration
+}
+
+// StartProfile starts recording the time spent in each form evaluated
+// in the Vm. Forms evaluated in spawned tasks are not recorded.
+func (vm *Vm) StartProfile() {
+	vm.Lock()
+	defer vm.Unlock()
+
+	vm.profile = newProfiler()
+}
+
+// StopProfile stops profiling the Vm, and returns the profile
+// recorded since StartProfile, or nil if it was not profiled.
+func (vm *Vm) StopProfile() *Profile {
+	vm.Lock()
+	defer vm.Unlock()
+
+	p := vm.profile
+	if p == nil {
+		return nil
+	}
+	vm.profile = nil
+
+	return &Profile{
+		root:     p.root,
+		started:  p.started,
+		duration: time.Since(p.started),
+	}
+}
+
+// Forms summarizes the profile by form, from the form
+// with the most total time to the form with the least.
+func (p *Profile) Forms() []FormProfile {
+	forms := make(map[profFunc]*FormProfile)
+	// invocations of a form within invocations of itself are already
+	// counted in the total time of the outermost invocation
+	active := make(map[profFunc]int)
+
+	var visit func(n *profNode)
+	visit = func(n *profNode) {
+		fp, prs := forms[n.fn]
+		if !prs {
+			fp = &FormProfile{
+				Name:     n.fn.name,
+				Position: n.fn.position(),
+			}
+			forms[n.fn] = fp
+		}
+		fp.Calls += n.calls
+		fp.Self += n.self()
+		if active[n.fn] == 0 {
+			fp.Total += n.total
+		}
+
+		active[n.fn]++
+		for _, child := range n.children {
+			visit(child)
+		}
+		active[n.fn]--
+	}
+	for _, child := range p.root.children {
+		visit(child)
+	}
+
+	summary := make([]FormProfile, 0, len(forms))
+	for _, fp := range forms {
+		summary = append(summary, *fp)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Total == summary[j].Total {
+			return summary[i].Name < summary[j].Name
+		}
+		return summary[i].Total > summary[j].Total
+	})
+	return summary
+}
+
+func (fn profFunc) position() string {
+	if fn.definition == nil {
+		return ""
+	}
+	return fn.definition.position.String()
+}
+
+// WritePprof writes the profile in the gzipped protobuf format read by
+// pprof, with a sample for each call stack. Samples record the number
+// of invocations and the self time of the form at the top of the stack,
+// and locations are the source positions of the forms' definitions.
+func (p *Profile) WritePprof(w io.Writer) error {
+	b := &pprofBuilder{
+		strings:   map[string]int64{"": 0},
+		stringTab: []string{""},
+		locations: make(map[profFunc]uint64),
+	}
+
+	b.valueType(1, "calls", "count")
+	b.valueType(1, "time", "nanoseconds")
+
+	var stack []uint64
+	var visit func(n *profNode)
+	visit = func(n *profNode) {
+		stack = append(stack, b.location(n.fn))
+		if n.calls > 0 {
+			// pprof stacks list the innermost location first
+			ids := make([]uint64, len(stack))
+			for i, id := range stack {
+				ids[len(stack)-1-i] = id
+			}
+			b.sample(ids, n.calls, int64(n.self()))
+		}
+		for _, child := range n.sortedChildren() {
+			visit(child)
+		}
+		stack = stack[:len(stack)-1]
+	}
+	for _, child := range p.root.sortedChildren() {
+		visit(child)
+	}
+
+	b.out.Write(b.locs.Bytes())
+	b.out.Write(b.funcs.Bytes())
+	b.out.int(9, p.started.UnixNano())
+	b.out.int(10, int64(p.duration))
+	b.valueType(11, "time", "nanoseconds")
+	b.out.int(12, 1)
+	b.out.int(14, b.str("time"))
+	// the string table is written last, once all strings are known
+	for _, s := range b.stringTab {
+		b.out.string(6, s)
+	}
+
+	gz := gzip.NewWriter(w)
+	if _, err := gz.Write(b.out.Bytes()); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func (n *profNode) sortedChildren() []*profNode {
+	children := make([]*profNode, 0, len(n.children))
+	for _, child := range n.children {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		a, b := children[i].fn, children[j].fn
+		if a.name == b.name {
+			return a.position() < b.position()
+		}
+		return a.name < b.name
+	})
+	return children
+}
+
+// pprofBuilder encodes a profile as the Profile message of pprof's
+// profile.proto. Locations and functions are written into their own
+// buffers as they are found, since they are fields of the profile.
+type pprofBuilder struct {
+	out   protoBuffer
+	locs  protoBuffer
+	funcs protoBuffer
+
+	strings   map[string]int64
+	stringTab []string
+	locations map[profFunc]uint64
+}
+
+func (b *pprofBuilder) str(s string) int64 {
+	if i, prs := b.strings[s]; prs {
+		return i
+	}
+	i := int64(len(b.stringTab))
+	b.strings[s] = i
+	b.stringTab = append(b.stringTab, s)
+	return i
+}
+
+func (b *pprofBuilder) valueType(field int, typ, unit string) {
+	var vt protoBuffer
+	vt.int(1, b.str(typ))
+	vt.int(2, b.str(unit))
+	b.out.bytes(field, vt.Bytes())
+}
+
+func (b *pprofBuilder) sample(locations []uint64, calls, nanos int64) {
+	var s protoBuffer
+	s.uints(1, locations)
+	s.ints(2, []int64{calls, nanos})
+	b.out.bytes(2, s.Bytes())
+}
+
+// location returns the id of the location of fn, which is
+// also the id of its function, adding both if they are new.
+func (b *pprofBuilder) location(fn profFunc) uint64 {
+	if id, prs := b.locations[fn]; prs {
+		return id
+	}
+	id := uint64(len(b.locations) + 1)
+	b.locations[fn] = id
+
+	name, filename, line := fn.name, "(native)", 0
+	if fn.definition != nil {
+		filename = fn.definition.position.path
+		line = fn.definition.position.line
+	}
+	if name == "" {
+		name = "(anonymous)"
+	}
+
+	var f protoBuffer
+	f.uint(1, id)
+	f.int(2, b.str(name))
+	f.int(3, b.str(name))
+	f.int(4, b.str(filename))
+	f.int(5, int64(line))
+	b.funcs.bytes(5, f.Bytes())
+
+	var ln protoBuffer
+	ln.uint(1, id)
+	ln.int(2, int64(line))
+	var loc protoBuffer
+	loc.uint(1, id)
+	loc.bytes(4, ln.Bytes())
+	b.locs.bytes(4, loc.Bytes())
+
+	return id
+}
+
+// protoBuffer encodes protocol buffer fields.
+type protoBuffer struct {
+	bytes.Buffer
+}
+
+func (pb *protoBuffer) varint(x uint64) {
+	for x >= 0x80 {
+		pb.WriteByte(byte(x) | 0x80)
+		x >>= 7
+	}
+	pb.WriteByte(byte(x))
+}
+
+func (pb *protoBuffer) key(field int, wireType int) {
+	pb.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (pb *protoBuffer) uint(field int, x uint64) {
+	pb.key(field, 0)
+	pb.varint(x)
+}
+
+func (pb *protoBuffer) int(field int, x int64) {
+	pb.uint(field, uint64(x))
+}
+
+func (pb *protoBuffer) bytes(field int, b []byte) {
+	pb.key(field, 2)
+	pb.varint(uint64(len(b)))
+	pb.Write(b)
+}
+
+func (pb *protoBuffer) string(field int, s string) {
+	pb.bytes(field, []byte(s))
+}
+
+// uints and ints write packed repeated fields
+func (pb *protoBuffer) uints(field int, xs []uint64) {
+	var packed protoBuffer
+	for _, x := range xs {
+		packed.varint(x)
+	}
+	pb.bytes(field, packed.Bytes())
+}
+
+func (pb *protoBuffer) ints(field int, xs []int64) {
+	var packed protoBuffer
+	for _, x := range xs {
+		packed.varint(uint64(x))
+	}
+	pb.bytes(field, packed.Bytes())
+}
@@ -0,0 +1,35 @@
+package xin
+
+import (
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+)
+
+// readProtocolMessage reads a message framed with a Content-Length
+// header, as used by both the Debug Adapter Protocol and the Language
+// Server Protocol. It returns io.EOF once the client closes its end.
+func readProtocolMessage(r *textproto.Reader) ([]byte, error) {
+	header, err := r.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length header: %s", err)
+	}
+	msg := make([]byte, length)
+	if _, err := io.ReadFull(r.R, msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// writeProtocolMessage writes a message framed with a Content-Length
+// header. Callers serialize writes to w.
+func writeProtocolMessage(w io.Writer, msg []byte) {
+	fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(msg), msg)
+}
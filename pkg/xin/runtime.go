@@ -1,13 +1,10 @@
 package xin
 
 import (
-	"bufio"
 	"io"
 	"math"
-	"math/rand"
-	"os"
 	"strings"
-	"time"
+	"sync"
 )
 
 type formEvaler func(*Frame, []Value, *astNode) (Value, InterpreterError)
@@ -15,6 +12,20 @@ type formEvaler func(*Frame, []Value, *astNode) (Value, InterpreterError)
 type NativeFormValue struct {
 	name   string
 	evaler formEvaler
+	// ref is set for native forms created at runtime, like forms
+	// converted from Go funcs, which may share a name with other
+	// forms, and are told apart by ref rather than by name
+	ref *formEvaler
+}
+
+// newNativeForm creates a native form that is not bound to its name
+// in a Vm, and is only equal to itself.
+func newNativeForm(name string, evaler formEvaler) NativeFormValue {
+	return NativeFormValue{
+		name:   name,
+		evaler: evaler,
+		ref:    &evaler,
+	}
 }
 
 func (v NativeFormValue) String() string {
@@ -27,26 +38,31 @@ func (v NativeFormValue) Repr() string {
 
 func (v NativeFormValue) Equal(o Value) bool {
 	if ov, ok := o.(NativeFormValue); ok {
-		return v.name == ov.name
+		return v.ref == ov.ref && v.name == ov.name
 	}
 
 	return false
 }
 
-func loadAllDefaultValues(vm *Vm) {
-	fr := vm.Frame
+func loadAllDefaultValues(vm *Vm, fr *Frame) {
 
-	stdoutStream := NewStream()
+	stdoutStream := vm.NewStream()
 	stdoutStream.callbacks.sink = func(v Value, node *astNode) InterpreterError {
-		os.Stdout.Write([]byte(v.String()))
+		vm.stdout.Write([]byte(v.String()))
 		return nil
 	}
 	fr.Put("os::stdout", stdoutStream)
+	vm.stdoutStream = stdoutStream
 
-	stdinStream := NewStream()
+	// reads from stdin run in async callbacks,
+	// so they must not read from the buffer at once
+	var stdinLock sync.Mutex
+	stdinStream := vm.NewStream()
 	stdinStream.callbacks.source = func() (Value, InterpreterError) {
-		reader := bufio.NewReader(os.Stdin)
-		input, err := reader.ReadString('\n')
+		stdinLock.Lock()
+		defer stdinLock.Unlock()
+
+		input, err := vm.stdin.ReadString('\n')
 		if err == io.EOF {
 			return StringValue(""), nil
 		} else if err != nil {
@@ -60,10 +76,7 @@ func loadAllDefaultValues(vm *Vm) {
 	fr.Put("os::stdin", stdinStream)
 }
 
-func loadAllNativeForms(vm *Vm) {
-	// seed PRNG for math::rand
-	rand.Seed(time.Now().UTC().UnixNano())
-
+func loadAllNativeForms(vm *Vm, fr *Frame) {
 	vm.evalers = map[string]formEvaler{
 		"+": addForm,
 		"-": subtractForm,
@@ -109,6 +122,35 @@ func loadAllNativeForms(vm *Vm) {
 		"map::size": mapSizeForm,
 		"map::keys": mapKeysForm,
 
+		"error":          errorForm,
+		"error::message": errorMessageForm,
+		"error::kind":    errorKindForm,
+		"error::pos":     errorPosForm,
+		"raise":          raiseForm,
+		"try":            tryForm,
+
+		"chan":         chanForm,
+		"chan::send!":  chanSendForm,
+		"chan::recv":   chanRecvForm,
+		"chan::close!": chanCloseForm,
+		"chan::select": chanSelectForm,
+		"spawn":        spawnForm,
+
+		"future":        futureForm,
+		"future::then":  futureThenForm,
+		"future::catch": futureCatchForm,
+		"future::all":   futureAllForm,
+		"future::race":  futureRaceForm,
+		"future::wait":  futureWaitForm,
+
+		"ast":         astForm,
+		"ast::lit":    astLitForm,
+		"ast::kind":   astKindForm,
+		"ast::value":  astValueForm,
+		"ast::leaves": astLeavesForm,
+		"ast::form":   astFormForm,
+		"ast::name":   astNameForm,
+
 		"stream":              streamForm,
 		"stream::set-sink!":   streamSetSink,
 		"stream::set-source!": streamSetSource,
@@ -130,17 +172,36 @@ func loadAllNativeForms(vm *Vm) {
 		"os::wait":   osWaitForm,
 		"os::stat":   osStatForm,
 		"os::open":   osOpenForm,
+		"os::seek":   osSeekForm,
+		"os::tell":   osTellForm,
 		"os::delete": osDeleteForm,
+		"os::dir":    osDirForm,
+		"os::mkdir":  osMkdirForm,
+		"os::rename": osRenameForm,
+		"os::copy":   osCopyForm,
+		"os::glob":   osGlobForm,
+		"os::walk":   osWalkForm,
+		"os::exec":   osExecForm,
 		"os::dial":   osDialForm,
 		"os::listen": osListenForm,
 		"os::log":    osLogForm,
 		"os::args":   osArgsForm,
+		"os::exit":   osExitForm,
 		"os::time":   osTimeForm,
 
+		"os::env":       osEnvForm,
+		"os::env::get":  osEnvGetForm,
+		"os::env::set!": osEnvSetForm,
+		"os::cwd":       osCwdForm,
+		"os::chdir":     osChdirForm,
+		"os::pid":       osPidForm,
+		"os::hostname":  osHostnameForm,
+
+		"test::report": testReportForm,
+
 		"debug::dump": debugDumpForm,
 	}
 
-	fr := vm.Frame
 	for name, evaler := range vm.evalers {
 		fr.Put(name, NativeFormValue{
 			name:   name,
@@ -0,0 +1,183 @@
+package xin
+
+// scopeInfo describes the names bound in one lexical scope, i.e. in the
+// body of a form definition, and the slot in which each name is stored
+// in frames created for that scope.
+//
+// Program files (and the repl) evaluate their top level in a frame
+// without a scopeInfo, whose names are bound dynamically by name.
+type scopeInfo struct {
+	parent *scopeInfo
+	names  []string
+	slots  map[string]int
+	// slot of each form argument, in order
+	params []int
+}
+
+func newScopeInfo(parent *scopeInfo) *scopeInfo {
+	return &scopeInfo{
+		parent: parent,
+		names:  []string{},
+		slots:  make(map[string]int),
+	}
+}
+
+func (sc *scopeInfo) declare(name string) int {
+	if slot, prs := sc.slots[name]; prs {
+		return slot
+	}
+
+	slot := len(sc.names)
+	sc.slots[name] = slot
+	sc.names = append(sc.names, name)
+	return slot
+}
+
+// resolve finds the lexical address of a name, as the number of scopes
+// up from sc and the slot within that scope.
+func (sc *scopeInfo) resolve(name string) (int, int, bool) {
+	depth := 0
+	for s := sc; s != nil; s = s.parent {
+		if slot, prs := s.slots[name]; prs {
+			return depth, slot, true
+		}
+		depth++
+	}
+
+	return 0, 0, false
+}
+
+// resolveNames is a static analysis pass run between parse and eval.
+// Because Xin is lexically scoped, every name that refers to a binding in
+// an enclosing form definition can be resolved to a (depth, slot) address
+// ahead of time, so the evaluator need not search maps by name.
+//
+// sc is the scope of the frame node will be evaluated in. No new names
+// are declared in sc itself, because frames for it may already exist.
+// Names that cannot be resolved statically, like those bound at the top
+// level of a program or by an import, are left to be looked up by name.
+func resolveNames(node *astNode, sc *scopeInfo) {
+	if !node.isForm {
+		if node.token.kind == tkName {
+			node.scoped = false
+			if sc == nil {
+				return
+			}
+
+			if depth, slot, ok := sc.resolve(node.token.value); ok {
+				node.scoped = true
+				node.depth = depth
+				node.slot = slot
+			}
+		}
+		return
+	}
+
+	if isDefinition(node) {
+		specimen, body := node.leaves[1], node.leaves[2]
+
+		resolveNames(specimen.leaves[0], sc)
+
+		// parameters are declared in the order in which bindArgs binds
+		// them: named parameters, then any rest parameter last
+		inner := newScopeInfo(sc)
+		params := specimen.leaves[1:]
+		defaults := []*astNode{}
+		rest := ""
+		for _, param := range params {
+			if name, ok := restParamName(param); ok {
+				rest = name
+			} else if nameNode, def, ok := optionalParam(param); ok {
+				inner.params = append(inner.params, inner.declare(nameNode.token.value))
+				defaults = append(defaults, def)
+			} else if !param.isForm && param.token.kind == tkName {
+				inner.params = append(inner.params, inner.declare(param.token.value))
+			}
+		}
+		if rest != "" {
+			inner.params = append(inner.params, inner.declare(rest))
+		}
+		for _, def := range defaults {
+			declareNames(def, inner)
+		}
+		declareNames(body, inner)
+		specimen.scope = inner
+
+		for _, def := range defaults {
+			resolveNames(def, inner)
+		}
+		resolveNames(body, inner)
+		return
+	}
+
+	for _, leaf := range node.leaves {
+		resolveNames(leaf, sc)
+	}
+}
+
+// declareNames declares every name bound within node in the scope sc,
+// without descending into the bodies of nested form definitions,
+// which create scopes of their own.
+func declareNames(node *astNode, sc *scopeInfo) {
+	if !node.isForm || len(node.leaves) == 0 {
+		return
+	}
+
+	if isDefinition(node) {
+		formNameNode := node.leaves[1].leaves[0]
+		if !formNameNode.isForm && formNameNode.token.kind == tkName {
+			sc.declare(formNameNode.token.value)
+		}
+		return
+	}
+
+	if isBind(node) {
+		specimen := node.leaves[1]
+		if !specimen.isForm && specimen.token.kind == tkName {
+			sc.declare(specimen.token.value)
+		}
+	}
+
+	for _, leaf := range node.leaves {
+		declareNames(leaf, sc)
+	}
+}
+
+func isBind(node *astNode) bool {
+	if len(node.leaves) != 3 {
+		return false
+	}
+
+	head := node.leaves[0]
+	return !head.isForm && (head.token.kind == tkBindForm || head.token.kind == tkMacroForm)
+}
+
+// isDefinition reports whether node is a form or macro
+// definition, which creates a new lexical scope.
+func isDefinition(node *astNode) bool {
+	if !isBind(node) {
+		return false
+	}
+
+	specimen := node.leaves[1]
+	return specimen.isForm && len(specimen.leaves) > 0
+}
+
+// copyAstNode deep-copies a syntax tree, dropping any resolved
+// names, so that it can be resolved again in a new context.
+func copyAstNode(node *astNode) *astNode {
+	n := &astNode{
+		isForm:   node.isForm,
+		token:    node.token,
+		position: node.position,
+	}
+
+	if node.isForm {
+		n.leaves = make([]*astNode, len(node.leaves))
+		for i, leaf := range node.leaves {
+			n.leaves[i] = copyAstNode(leaf)
+		}
+	}
+
+	return n
+}
@@ -1,19 +1,27 @@
 package xin
 
-import "fmt"
-
-var streamId int64 = 0
+import (
+	"fmt"
+	"sync/atomic"
+)
 
 type sinkCallback func(Value, *astNode) InterpreterError
 
 type sourceCallback func() (Value, InterpreterError)
 
-type closerCallback func() InterpreterError
+// closerCallback is called with the Vm closing the
+// stream, which is locked while it closes the stream.
+type closerCallback func(*Vm) InterpreterError
+
+// seekCallback moves the offset of a stream backed by a file relative
+// to whence, like io.Seeker, and returns the new offset.
+type seekCallback func(offset int64, whence int) (int64, error)
 
 type streamCallbacks struct {
 	sink   sinkCallback
 	source sourceCallback
 	closer closerCallback
+	seeker seekCallback
 }
 
 type StreamValue struct {
@@ -22,10 +30,17 @@ type StreamValue struct {
 	callbacks *streamCallbacks
 }
 
+// hostStreamIDs counts streams created with NewStream, outside of any
+// Vm. They are numbered down from -1, so that their ids never collide
+// with those of streams created in a Vm. It is accessed atomically.
+var hostStreamIDs int64
+
+// NewStream creates a new stream with no callbacks set.
+//
+// Deprecated: Use Vm.NewStream, which creates the stream in a Vm.
 func NewStream() StreamValue {
-	streamId++
 	return StreamValue{
-		id:        streamId,
+		id:        atomic.AddInt64(&hostStreamIDs, -1),
 		callbacks: &streamCallbacks{},
 	}
 }
@@ -42,6 +57,10 @@ func (v StreamValue) isClose() bool {
 	return v.callbacks.closer != nil
 }
 
+func (v StreamValue) isSeek() bool {
+	return v.callbacks.seeker != nil
+}
+
 func (v StreamValue) String() string {
 	streamType := ""
 	if v.isSink() {
@@ -53,6 +72,9 @@ func (v StreamValue) String() string {
 	if v.isClose() {
 		streamType += "close "
 	}
+	if v.isSeek() {
+		streamType += "seek "
+	}
 	return fmt.Sprintf("(%s<stream %d>)", streamType, v.id)
 }
 
@@ -69,7 +91,7 @@ func (v StreamValue) Equal(o Value) bool {
 }
 
 func streamForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
-	return NewStream(), nil
+	return fr.Vm.NewStream(), nil
 }
 
 func streamSetSink(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
@@ -97,12 +119,15 @@ func streamSetSink(fr *Frame, args []Value, node *astNode) (Value, InterpreterEr
 	}
 
 	if fok && sok {
+		// stream callbacks are called off the event loop, so
+		// callbacks defined in Xin are sent back to run on it
+		vm := fr.Vm
+		ctx := vm.ctx
 		firstStream.callbacks.sink = func(v Value, node *astNode) InterpreterError {
-			fr.Vm.Lock()
-			defer fr.Vm.Unlock()
-
-			_, err := unlazyEvalFormWithArgs(fr, secondForm, []Value{v}, node)
-			return err
+			return vm.loop.call(ctx, "stream::set-sink!", node, func() InterpreterError {
+				_, err := unlazyEvalCallback(fr, secondForm, []Value{v}, node)
+				return err
+			})
 		}
 
 		return secondForm, nil
@@ -127,17 +152,22 @@ func streamSetSource(fr *Frame, args []Value, node *astNode) (Value, Interpreter
 
 	if firstStream, ok := first.(StreamValue); ok {
 		if secondForm, ok := second.(FormValue); ok {
-			if len(*secondForm.arguments) != 0 {
+			if secondForm.arguments.required() != 0 {
 				return nil, InvalidStreamCallbackError{
 					reason: "Mismatched argument count in callback",
 				}
 			}
 
+			vm := fr.Vm
+			ctx := vm.ctx
 			firstStream.callbacks.source = func() (Value, InterpreterError) {
-				fr.Vm.Lock()
-				defer fr.Vm.Unlock()
-
-				return unlazyEvalFormWithArgs(fr, secondForm, []Value{}, node)
+				var rv Value
+				err := vm.loop.call(ctx, "stream::set-source!", node, func() InterpreterError {
+					var err InterpreterError
+					rv, err = unlazyEvalCallback(fr, secondForm, []Value{}, node)
+					return err
+				})
+				return rv, err
 			}
 
 			return secondForm, nil
@@ -163,19 +193,29 @@ func streamSetClose(fr *Frame, args []Value, node *astNode) (Value, InterpreterE
 
 	if firstStream, ok := first.(StreamValue); ok {
 		if secondForm, ok := second.(FormValue); ok {
-			if len(*secondForm.arguments) != 0 {
+			if secondForm.arguments.required() != 0 {
 				return nil, InvalidStreamCallbackError{
 					reason: "Mismatched argument count in callback",
 				}
 			}
 
-			firstStream.callbacks.closer = func() InterpreterError {
-				fr.Vm.Lock()
-				defer fr.Vm.Unlock()
+			vm := fr.Vm
+			ctx := vm.ctx
+			closeStream := func() InterpreterError {
+				_, err := unlazyEvalCallback(fr, secondForm, []Value{}, node)
+				return err
+			}
+			firstStream.callbacks.closer = func(caller *Vm) InterpreterError {
+				if caller == vm {
+					return closeStream()
+				}
 
-				localFrame := newFrame(fr)
-				eval(localFrame, secondForm.definition)
-				return nil
+				// the stream belongs to another task
+				var err InterpreterError
+				caller.block(func() {
+					err = vm.loop.call(ctx, "stream::set-close!", node, closeStream)
+				})
+				return err
 			}
 
 			return secondForm, nil
@@ -188,6 +228,8 @@ func streamSetClose(fr *Frame, args []Value, node *astNode) (Value, InterpreterE
 	}
 }
 
+// streamSourceForm reads a value from a stream, and calls a callback
+// with it. Without a callback, it returns a future of the value.
 func streamSourceForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
 	if len(args) < 1 {
 		return nil, IncorrectNumberOfArgsError{
@@ -197,22 +239,18 @@ func streamSourceForm(fr *Frame, args []Value, node *astNode) (Value, Interprete
 		}
 	}
 
-	first := args[0]
-	var second Value
-	if len(args) >= 2 {
-		second = args[1]
-	} else {
-		second = Noop
-	}
-
+	firstStream, fok := args[0].(StreamValue)
 	var secondForm Value
-	firstStream, fok := first.(StreamValue)
-	secondForm, sok := second.(FormValue)
-	if !sok {
-		secondForm, sok = second.(NativeFormValue)
+	if len(args) >= 2 {
+		switch args[1].(type) {
+		case FormValue, NativeFormValue:
+			secondForm = args[1]
+		default:
+			fok = false
+		}
 	}
 
-	if fok && sok {
+	if fok {
 		if !firstStream.isSource() {
 			return nil, InvalidStreamCallbackError{
 				reason: "Cannot try to source from a non-source stream",
@@ -220,26 +258,34 @@ func streamSourceForm(fr *Frame, args []Value, node *astNode) (Value, Interprete
 		}
 
 		vm := fr.Vm
-		vm.waiter.Add(1)
-		go func() {
-			defer vm.waiter.Done()
+		op := vm.loop.start(vm.ctx, "->", node)
 
+		var fut FutureValue
+		var result Value = zeroValue
+		if secondForm == nil {
+			fut = newFutureValue(vm)
+			result = fut
+		}
+
+		vm.loop.goStream(firstStream, func() {
 			rv, err := firstStream.callbacks.source()
-			if err != nil {
-				fmt.Println(FormatError(err))
+			if secondForm == nil {
+				fut.settleWith(rv, err)
+				op.complete(nil)
 				return
 			}
 
-			vm.Lock()
-			defer vm.Unlock()
+			op.complete(func() InterpreterError {
+				if err != nil {
+					return err
+				}
 
-			_, err = unlazyEvalFormWithArgs(fr, secondForm, []Value{rv}, node)
-			if err != nil {
-				fmt.Println(FormatError(err))
-			}
-		}()
+				_, err := unlazyEvalCallback(fr, secondForm, []Value{rv}, node)
+				return err
+			})
+		})
 
-		return zeroValue, nil
+		return result, nil
 	}
 
 	return nil, MismatchedArgumentsError{
@@ -248,6 +294,9 @@ func streamSourceForm(fr *Frame, args []Value, node *astNode) (Value, Interprete
 	}
 }
 
+// streamSinkForm writes a value to a stream, and calls a callback with
+// whether the write succeeded. Without a callback, it returns a future
+// of whether the write succeeded.
 func streamSinkForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
 	if len(args) < 2 {
 		return nil, IncorrectNumberOfArgsError{
@@ -258,21 +307,19 @@ func streamSinkForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterE
 	}
 
 	first, second := args[0], args[1]
-	var third Value
-	if len(args) >= 3 {
-		third = args[2]
-	} else {
-		third = Noop
-	}
 
-	var thirdForm Value
 	firstStream, fok := first.(StreamValue)
-	thirdForm, tok := third.(FormValue)
-	if !tok {
-		thirdForm, tok = third.(NativeFormValue)
+	var thirdForm Value
+	if len(args) >= 3 {
+		switch args[2].(type) {
+		case FormValue, NativeFormValue:
+			thirdForm = args[2]
+		default:
+			fok = false
+		}
 	}
 
-	if fok && tok {
+	if fok {
 		if !firstStream.isSink() {
 			return nil, InvalidStreamCallbackError{
 				reason: "Cannot try to sink to a non-sink stream",
@@ -280,32 +327,44 @@ func streamSinkForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterE
 		}
 
 		vm := fr.Vm
-		vm.waiter.Add(1)
-		go func() {
-			defer vm.waiter.Done()
+		op := vm.loop.start(vm.ctx, "<-", node)
 
-			success := trueValue
+		var fut FutureValue
+		var result Value = zeroValue
+		if thirdForm == nil {
+			fut = newFutureValue(vm)
+			result = fut
+		}
 
+		vm.loop.goStream(firstStream, func() {
+			// a sink that fails at runtime, like a write to a
+			// closed connection, reports false rather than an error
+			var success Value = trueValue
 			err := firstStream.callbacks.sink(second, node)
 			if err != nil {
-				if _, ok := err.(RuntimeError); ok {
+				if _, ok := unwrapError(err).(RuntimeError); ok {
 					success = falseValue
-				} else {
-					fmt.Println(FormatError(err))
+					err = nil
 				}
+			}
+
+			if thirdForm == nil {
+				fut.settleWith(success, err)
+				op.complete(nil)
 				return
 			}
 
-			vm.Lock()
-			defer vm.Unlock()
+			op.complete(func() InterpreterError {
+				if err != nil {
+					return err
+				}
 
-			_, err = unlazyEvalFormWithArgs(fr, thirdForm, []Value{success}, node)
-			if err != nil {
-				fmt.Println(FormatError(err))
-			}
-		}()
+				_, err := unlazyEvalCallback(fr, thirdForm, []Value{success}, node)
+				return err
+			})
+		})
 
-		return zeroValue, nil
+		return result, nil
 	}
 
 	return nil, MismatchedArgumentsError{
@@ -332,7 +391,7 @@ func streamCloseForm(fr *Frame, args []Value, node *astNode) (Value, Interpreter
 			}
 		}
 
-		err := firstStream.callbacks.closer()
+		err := firstStream.callbacks.closer(fr.Vm)
 		if err != nil {
 			return nil, err
 		}
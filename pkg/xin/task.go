@@ -0,0 +1,660 @@
+package xin
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ChanValue is a channel over which tasks pass messages. Messages are
+// copied when they are sent, so tasks never share mutable values.
+type ChanValue struct {
+	underlying *chanUnderlying
+}
+
+type chanUnderlying struct {
+	ch chan Value
+	// done is closed when the chan is closed. The underlying Go
+	// channel itself is never closed, so that sends racing with
+	// a close fail gracefully rather than panicking.
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newChanValue(size int) ChanValue {
+	return ChanValue{
+		underlying: &chanUnderlying{
+			ch:   make(chan Value, size),
+			done: make(chan struct{}),
+		},
+	}
+}
+
+func (v ChanValue) String() string {
+	return fmt.Sprintf("(<chan %d>)", cap(v.underlying.ch))
+}
+
+func (v ChanValue) Repr() string {
+	return v.String()
+}
+
+func (v ChanValue) Equal(o Value) bool {
+	if ov, ok := o.(ChanValue); ok {
+		return v.underlying == ov.underlying
+	}
+
+	return false
+}
+
+func (v ChanValue) close() {
+	v.underlying.closeOnce.Do(func() {
+		close(v.underlying.done)
+	})
+}
+
+// tryRecv receives a value if one is ready without blocking.
+func (v ChanValue) tryRecv() (Value, bool) {
+	select {
+	case val := <-v.underlying.ch:
+		return val, true
+	default:
+		return nil, false
+	}
+}
+
+// copier deep-copies values so they can be handed to another task,
+// preserving any aliasing between the copied values. Copied forms
+// close over copies of their frames, belonging to the Vm vm. Without
+// a vm, as for messages sent over chans, forms cannot be copied.
+//
+// A copied frame only holds copies of the names that the forms closing
+// over it may look up, so copying a form does not copy every value in
+// the frames above it, like the global frame. Shared frames, which hold
+// the standard library, are not copied at all.
+//
+// Streams, chans, and futures are handles to resources shared between
+// tasks, so they are not copied.
+type copier struct {
+	vm     *Vm
+	frames map[*Frame]*Frame
+	// bound holds the names already copied into each copied frame
+	bound map[*Frame]map[string]bool
+	// names caches the names looked up by each copied form definition
+	names map[*astNode]map[string]bool
+	vecs  map[*vecUnderlying]VecValue
+	maps  map[*mapItems]MapValue
+	cwds  map[*string]*string
+}
+
+func newCopier(vm *Vm) *copier {
+	return &copier{
+		vm:     vm,
+		frames: make(map[*Frame]*Frame),
+		bound:  make(map[*Frame]map[string]bool),
+		names:  make(map[*astNode]map[string]bool),
+		vecs:   make(map[*vecUnderlying]VecValue),
+		maps:   make(map[*mapItems]MapValue),
+		cwds:   make(map[*string]*string),
+	}
+}
+
+func (c *copier) value(v Value) (Value, bool) {
+	switch val := v.(type) {
+	case StringValue:
+		return StringValue(append([]byte{}, val...)), true
+	case VecValue:
+		if cp, prs := c.vecs[val.underlying]; prs {
+			return cp, true
+		}
+
+		items := make([]Value, len(val.underlying.items))
+		cp := NewVecValue(items)
+		c.vecs[val.underlying] = cp
+		for i, item := range val.underlying.items {
+			itemCopy, ok := c.value(item)
+			if !ok {
+				return nil, false
+			}
+			items[i] = itemCopy
+		}
+		return cp, true
+	case MapValue:
+		if cp, prs := c.maps[val.items]; prs {
+			return cp, true
+		}
+
+		cp := NewMapValue()
+		c.maps[val.items] = cp
+		for k, item := range *val.items {
+			// keys are already hashable, and hashable
+			// proxies for strings are immutable
+			keyCopy, ok := c.value(k)
+			if !ok {
+				return nil, false
+			}
+			itemCopy, ok := c.value(item)
+			if !ok {
+				return nil, false
+			}
+			(*cp.items)[keyCopy] = itemCopy
+		}
+		return cp, true
+	case FormValue:
+		if c.vm == nil {
+			return nil, false
+		}
+		val.frame = c.closure(val.frame, c.formNames(val.definition, val.arguments))
+		return val, true
+	case MacroFormValue:
+		if c.vm == nil {
+			return nil, false
+		}
+		val.frame = c.closure(val.frame, c.formNames(val.definition, val.arguments))
+		return val, true
+	case LazyValue:
+		if c.vm == nil {
+			return nil, false
+		}
+		val.frame = c.closure(val.frame, c.formNames(val.node, nil))
+		return val, true
+	default:
+		// ints, fracs, native forms, errors, and syntax are immutable,
+		// and streams, chans, and futures are shared
+		return v, true
+	}
+}
+
+// formNames returns the names that evaluating a form definition may
+// look up: every name in the definition and the defaults of its
+// parameters, and every string, as macros may make names of strings.
+func (c *copier) formNames(def *astNode, args *argList) map[string]bool {
+	if names, prs := c.names[def]; prs {
+		return names
+	}
+
+	names := make(map[string]bool)
+	var collect func(node *astNode)
+	collect = func(node *astNode) {
+		if node == nil {
+			return
+		}
+		if !node.isForm && (node.token.kind == tkName || node.token.kind == tkStringLiteral) {
+			names[node.token.value] = true
+		}
+		for _, leaf := range node.leaves {
+			collect(leaf)
+		}
+	}
+	collect(def)
+	if args != nil {
+		for _, d := range args.defaults {
+			collect(d)
+		}
+	}
+
+	c.names[def] = names
+	return names
+}
+
+// closure copies the chain of frames a form closes over, with copies
+// of the given names wherever they are bound in it.
+func (c *copier) closure(fr *Frame, names map[string]bool) *Frame {
+	cp := c.frame(fr)
+	for f := fr; f != nil && !f.shared; f = f.Parent {
+		for name := range names {
+			c.bind(f, name)
+		}
+	}
+	return cp
+}
+
+// cwd returns the copy of the working directory of a file, which
+// os::chdir in the task changes without affecting its parent.
+func (c *copier) cwd(cwd *string) *string {
+	if cwd == nil {
+		return nil
+	}
+	if cp, prs := c.cwds[cwd]; prs {
+		return cp
+	}
+
+	cp := *cwd
+	c.cwds[cwd] = &cp
+	c.vm.cwds = append(c.vm.cwds, &cp)
+	return &cp
+}
+
+// frame returns the copy of a frame, which starts out with no names
+// bound. Shared frames are not copied.
+func (c *copier) frame(fr *Frame) *Frame {
+	if fr == nil || fr.shared {
+		return fr
+	}
+	if cp, prs := c.frames[fr]; prs {
+		return cp
+	}
+
+	cp := &Frame{
+		Vm:    c.vm,
+		scope: fr.scope,
+		cwd:   c.cwd(fr.cwd),
+	}
+	if fr.Scope != nil {
+		cp.Scope = make(map[string]Value)
+	}
+	if fr.slots != nil {
+		cp.slots = make([]Value, len(fr.slots))
+	}
+	c.frames[fr] = cp
+	c.bound[fr] = make(map[string]bool)
+	cp.Parent = c.frame(fr.Parent)
+
+	return cp
+}
+
+// bind copies the value of a name bound in a frame into its copy.
+func (c *copier) bind(fr *Frame, name string) {
+	bound := c.bound[fr]
+	if bound[name] {
+		return
+	}
+	// the name is marked before its value is copied,
+	// so that forms that refer to themselves terminate
+	bound[name] = true
+
+	// every frame belongs to the Vm being copied into,
+	// so values in frames can always be copied
+	cp := c.frames[fr]
+	if fr.scope != nil {
+		if slot, prs := fr.scope.slots[name]; prs && fr.slots[slot] != nil {
+			cp.slots[slot], _ = c.value(fr.slots[slot])
+		}
+	}
+	if val, prs := fr.Scope[name]; prs {
+		cp.Scope[name], _ = c.value(val)
+	}
+}
+
+// copyMessage copies a value to be sent to another task.
+func copyMessage(v Value, node *astNode) (Value, InterpreterError) {
+	cp, ok := newCopier(nil).value(v)
+	if !ok {
+		return nil, RuntimeError{
+			reason:   "Cannot send a form to another task: " + v.String(),
+			position: node.position,
+		}
+	}
+
+	return cp, nil
+}
+
+// taskVm creates the Vm in which a spawned task runs. A task Vm has its
+// own lock, so tasks can run in parallel with the Vm that spawned them.
+// It shares the spawning Vm's standard streams and evaluation context.
+func (vm *Vm) taskVm() *Vm {
+	evalers := make(map[string]formEvaler, len(vm.evalers))
+	for name, evaler := range vm.evalers {
+		evalers[name] = evaler
+	}
+
+	task := &Vm{
+		backend:      vm.backend,
+		imports:      make(map[string]*Frame),
+		evalers:      evalers,
+		ctx:          vm.ctx,
+		interrupted:  vm.interrupted,
+		exit:         vm.exit,
+		streamIDs:    vm.streamIDs,
+		stdin:        vm.stdin,
+		stdout:       vm.stdout,
+		stderr:       vm.stderr,
+		stdoutStream: vm.stdoutStream,
+		args:         vm.args,
+		rand:         rand.New(rand.NewSource(vm.rand.Int63())),
+		coverage:     vm.coverage,
+		tests:        vm.tests,
+	}
+	task.loop = newEventLoop(task)
+	return task
+}
+
+// block runs fn, which may block for a long time, with the Vm lock
+// released so that other callbacks and tasks can run in the meantime.
+// Natives are always evaluated with the Vm lock held.
+func (vm *Vm) block(fn func()) {
+	if vm.profile != nil {
+		cur := vm.profile.suspend()
+		defer vm.profile.resume(cur)
+	}
+
+	active := vm.loop.handOff()
+	vm.Unlock()
+	defer func() {
+		vm.Lock()
+		vm.loop.resume(active)
+	}()
+
+	fn()
+}
+
+func chanForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	size := 0
+	if len(args) >= 1 {
+		firstInt, ok := args[0].(IntValue)
+		if !ok || firstInt < 0 {
+			return nil, MismatchedArgumentsError{
+				node: node,
+				args: args,
+			}
+		}
+		size = int(firstInt)
+	}
+
+	return newChanValue(size), nil
+}
+
+func chanSendForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 2 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 2,
+			given:    len(args),
+		}
+	}
+
+	first, second := args[0], args[1]
+
+	firstChan, ok := first.(ChanValue)
+	if !ok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	msg, err := copyMessage(second, node)
+	if err != nil {
+		return nil, err
+	}
+
+	vm := fr.Vm
+	ctx := vm.ctx
+	sent := false
+	vm.block(func() {
+		select {
+		case firstChan.underlying.ch <- msg:
+			sent = true
+		case <-firstChan.underlying.done:
+		case <-ctx.Done():
+		}
+	})
+
+	if ctx.Err() != nil {
+		return nil, InterruptedError{
+			position: node.position,
+		}
+	}
+	if !sent {
+		return nil, RuntimeError{
+			reason:   "Cannot send to a closed chan",
+			position: node.position,
+		}
+	}
+
+	return second, nil
+}
+
+// chanRecvForm receives a message from a chan, or 0 once
+// the chan is closed and no more messages are buffered.
+func chanRecvForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	firstChan, ok := args[0].(ChanValue)
+	if !ok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	if val, ok := firstChan.tryRecv(); ok {
+		return val, nil
+	}
+
+	vm := fr.Vm
+	ctx := vm.ctx
+	var msg Value = zeroValue
+	vm.block(func() {
+		select {
+		case msg = <-firstChan.underlying.ch:
+		case <-firstChan.underlying.done:
+			if val, ok := firstChan.tryRecv(); ok {
+				msg = val
+			}
+		case <-ctx.Done():
+		}
+	})
+
+	if ctx.Err() != nil {
+		return nil, InterruptedError{
+			position: node.position,
+		}
+	}
+
+	return msg, nil
+}
+
+func chanCloseForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	firstChan, ok := args[0].(ChanValue)
+	if !ok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	firstChan.close()
+	return firstChan, nil
+}
+
+// chanSelectForm receives a message from whichever of a vec of chans
+// is ready first, and returns a vec of the chan's index and the message.
+// With a timeout in seconds, it returns (vec -1 0) if no chan is ready
+// in time.
+func chanSelectForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	firstVec, ok := args[0].(VecValue)
+	if !ok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	chans := make([]ChanValue, len(firstVec.underlying.items))
+	for i, item := range firstVec.underlying.items {
+		itemChan, ok := item.(ChanValue)
+		if !ok {
+			return nil, MismatchedArgumentsError{
+				node: node,
+				args: args,
+			}
+		}
+		chans[i] = itemChan
+	}
+
+	var timeout <-chan time.Time
+	if len(args) >= 2 {
+		var duration float64
+		switch second := args[1].(type) {
+		case IntValue:
+			duration = float64(second)
+		case FracValue:
+			duration = float64(second)
+		default:
+			return nil, MismatchedArgumentsError{
+				node: node,
+				args: args,
+			}
+		}
+
+		timer := time.NewTimer(time.Duration(duration * float64(time.Second)))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	// each chan contributes a case for receiving a message,
+	// and a case for the chan closing, followed by the timeout
+	// and the context of the evaluation
+	vm := fr.Vm
+	ctx := vm.ctx
+	cases := make([]reflect.SelectCase, 0, len(chans)*2+2)
+	for _, c := range chans {
+		cases = append(cases,
+			reflect.SelectCase{
+				Dir:  reflect.SelectRecv,
+				Chan: reflect.ValueOf(c.underlying.ch),
+			},
+			reflect.SelectCase{
+				Dir:  reflect.SelectRecv,
+				Chan: reflect.ValueOf(c.underlying.done),
+			},
+		)
+	}
+	cases = append(cases,
+		reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(timeout),
+		},
+		reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(ctx.Done()),
+		},
+	)
+
+	var chosen int
+	var recv reflect.Value
+	vm.block(func() {
+		chosen, recv, _ = reflect.Select(cases)
+	})
+
+	if ctx.Err() != nil {
+		return nil, InterruptedError{
+			position: node.position,
+		}
+	}
+
+	if chosen >= len(chans)*2 {
+		return NewVecValue([]Value{IntValue(-1), zeroValue}), nil
+	}
+
+	index := chosen / 2
+	var msg Value = zeroValue
+	if chosen%2 == 0 {
+		msg = recv.Interface().(Value)
+	} else if val, ok := chans[index].tryRecv(); ok {
+		msg = val
+	}
+
+	return NewVecValue([]Value{IntValue(index), msg}), nil
+}
+
+// spawnForm invokes a form with the given arguments as a task, which
+// may run in parallel with the rest of the program. The form and its
+// arguments are copied into the task, so the task shares no mutable
+// values with the rest of the program, and can only communicate with it
+// through chans. spawn returns a chan that receives the result of the
+// form, or an error value if the task fails.
+func spawnForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	switch args[0].(type) {
+	case FormValue, NativeFormValue:
+	default:
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	vm := fr.Vm
+	task := vm.taskVm()
+
+	c := newCopier(task)
+	task.Frame = c.frame(vm.Frame)
+	form, _ := c.value(args[0])
+	taskArgs := make([]Value, len(args)-1)
+	for i, arg := range args[1:] {
+		taskArgs[i], _ = c.value(arg)
+	}
+
+	result := newChanValue(1)
+	ctx := vm.ctx
+	interrupted := vm.interrupted
+
+	op := vm.loop.start(ctx, "spawn", node)
+	go func() {
+		defer op.complete(nil)
+
+		task.Lock()
+		val, err := unlazyEvalFormWithArgs(task.Frame, form, taskArgs, node)
+		if err != nil {
+			err = withStackTrace(err, task.stack)
+		}
+		task.Unlock()
+
+		if err != nil {
+			if ctx.Err() == nil {
+				vm.reportError(err)
+			}
+			val = errorValueFromError(err)
+		} else if msg, err := copyMessage(val, node); err != nil {
+			val = errorValueFromError(err)
+		} else {
+			val = msg
+		}
+		// the result chan has room for the result, unless the
+		// program has sent its own messages over it
+		select {
+		case result.underlying.ch <- val:
+		default:
+		}
+
+		// the task is done once its own async callbacks have run
+		task.wait(ctx, interrupted)
+		for _, err := range task.AsyncErrors() {
+			vm.loop.reportError(err)
+		}
+	}()
+
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package xin
+
+import (
+	"sync"
+)
+
+// TestCase is the result of a case in a scope of the test library,
+// as reported by test::scope.
+type TestCase struct {
+	Scope  string `json:"scope"`
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	// Message explains why the case failed
+	Message string `json:"message,omitempty"`
+}
+
+// testLog collects the test cases reported in a Vm and the
+// tasks it spawns, which share it.
+type testLog struct {
+	sync.Mutex
+	cases []TestCase
+}
+
+// TestCases returns the results of the test cases that programs
+// run in the Vm have reported through the test library, in order.
+func (vm *Vm) TestCases() []TestCase {
+	vm.tests.Lock()
+	defer vm.tests.Unlock()
+
+	cases := make([]TestCase, len(vm.tests.cases))
+	copy(cases, vm.tests.cases)
+	return cases
+}
+
+// testReportForm is the hook through which test::scope reports the
+// result of each case, which is 0 if the case passed, or a message.
+func testReportForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 3 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 3,
+			given:    len(args),
+		}
+	}
+
+	scope, name, result := args[0], args[1], args[2]
+	tc := TestCase{
+		Scope:  scope.String(),
+		Name:   name.String(),
+		Passed: result.Equal(zeroValue),
+	}
+	if !tc.Passed {
+		tc.Message = result.String()
+	}
+
+	tests := fr.Vm.tests
+	tests.Lock()
+	defer tests.Unlock()
+
+	tests.cases = append(tests.cases, tc)
+	return zeroValue, nil
+}
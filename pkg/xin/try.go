@@ -0,0 +1,281 @@
+package xin
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// ErrorValue is a first-class Xin error. Errors raised while evaluating
+// Xin code are caught by try as ErrorValues, and natives that can fail at
+// runtime, like os::open, return ErrorValues rather than raising.
+type ErrorValue struct {
+	message string
+	// kind names the class of error, like "not-found" or
+	// "undefined-name", so programs can handle errors by kind
+	kind     string
+	position position
+}
+
+func (v ErrorValue) String() string {
+	return "(<error> " + v.kind + ": " + v.message + ")"
+}
+
+func (v ErrorValue) Repr() string {
+	return "(<error> " + v.kind + ": " + v.message + " at " + v.position.String() + ")"
+}
+
+func (v ErrorValue) Equal(o Value) bool {
+	if ov, ok := o.(ErrorValue); ok {
+		return v == ov
+	}
+
+	return false
+}
+
+// raisedError is the InterpreterError that carries
+// an ErrorValue raised by Xin code up the stack.
+type raisedError struct {
+	value ErrorValue
+}
+
+func (e raisedError) Error() string {
+	if e.value.kind == "error" {
+		return "Raised error: " + e.value.message
+	}
+	return "Raised " + e.value.kind + " error: " + e.value.message
+}
+
+func (e raisedError) pos() position {
+	return e.value.position
+}
+
+// errorKind names the kind of ErrorValue an InterpreterError becomes
+// when it is caught by try.
+func errorKind(e InterpreterError) string {
+	switch err := unwrapError(e).(type) {
+	case raisedError:
+		return err.value.kind
+	case UndefinedNameError:
+		return "undefined-name"
+	case InvalidFormError, InvalidBindError, InvalidIfError, InvalidImportError:
+		return "invalid-form"
+	case InvalidIfConditionError:
+		return "invalid-if-condition"
+	case UnexpectedCharacterError, UnexpectedTokenError, UnexpectedEndingError:
+		return "syntax"
+	case IncorrectNumberOfArgsError:
+		return "arity"
+	case MismatchedArgumentsError:
+		return "mismatched-args"
+	case InvalidStreamCallbackError:
+		return "stream"
+	case NetworkError:
+		return "network"
+	default:
+		return "runtime"
+	}
+}
+
+func errorValueFromError(e InterpreterError) ErrorValue {
+	if raised, ok := unwrapError(e).(raisedError); ok {
+		return raised.value
+	}
+
+	return ErrorValue{
+		message:  unwrapError(e).Error(),
+		kind:     errorKind(e),
+		position: e.pos(),
+	}
+}
+
+// osErrorValue reports a failed operating system call as an ErrorValue.
+func osErrorValue(err error, node *astNode) ErrorValue {
+	kind := "os"
+	switch {
+	case os.IsNotExist(err), errors.Is(err, exec.ErrNotFound):
+		kind = "not-found"
+	case os.IsExist(err):
+		kind = "exists"
+	case os.IsPermission(err):
+		kind = "permission"
+	}
+
+	return ErrorValue{
+		message:  err.Error(),
+		kind:     kind,
+		position: node.position,
+	}
+}
+
+func networkErrorValue(err error, node *astNode) ErrorValue {
+	return ErrorValue{
+		message:  err.Error(),
+		kind:     "network",
+		position: node.position,
+	}
+}
+
+func errorForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	firstStr, fok := args[0].(StringValue)
+	if !fok {
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	kind := "error"
+	if len(args) >= 2 {
+		secondStr, sok := args[1].(StringValue)
+		if !sok {
+			return nil, MismatchedArgumentsError{
+				node: node,
+				args: args,
+			}
+		}
+		kind = string(secondStr)
+	}
+
+	return ErrorValue{
+		message:  string(firstStr),
+		kind:     kind,
+		position: node.position,
+	}, nil
+}
+
+func errorMessageForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	if firstErr, ok := args[0].(ErrorValue); ok {
+		return StringValue(firstErr.message), nil
+	}
+
+	return nil, MismatchedArgumentsError{
+		node: node,
+		args: args,
+	}
+}
+
+func errorKindForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	if firstErr, ok := args[0].(ErrorValue); ok {
+		return StringValue(firstErr.kind), nil
+	}
+
+	return nil, MismatchedArgumentsError{
+		node: node,
+		args: args,
+	}
+}
+
+func errorPosForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	if firstErr, ok := args[0].(ErrorValue); ok {
+		return StringValue(firstErr.position.String()), nil
+	}
+
+	return nil, MismatchedArgumentsError{
+		node: node,
+		args: args,
+	}
+}
+
+// raiseForm raises an ErrorValue, or a new error with the given message.
+func raiseForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	switch first := args[0].(type) {
+	case ErrorValue:
+		return nil, raisedError{value: first}
+	case StringValue:
+		return nil, raisedError{
+			value: ErrorValue{
+				message:  string(first),
+				kind:     "error",
+				position: node.position,
+			},
+		}
+	}
+
+	return nil, MismatchedArgumentsError{
+		node: node,
+		args: args,
+	}
+}
+
+// tryForm evaluates the form body with no arguments. If evaluating it
+// raises an error, try evaluates to the result of calling handler with
+// the error as an ErrorValue, or to the ErrorValue if there is no handler.
+// An interrupted evaluation cannot be caught.
+func tryForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
+	if len(args) < 1 {
+		return nil, IncorrectNumberOfArgsError{
+			node:     node,
+			required: 1,
+			given:    len(args),
+		}
+	}
+
+	body := args[0]
+	switch body.(type) {
+	case FormValue, NativeFormValue:
+	default:
+		return nil, MismatchedArgumentsError{
+			node: node,
+			args: args,
+		}
+	}
+
+	val, err := unlazyEvalFormWithArgs(fr, body, []Value{}, node)
+	if err == nil {
+		return val, nil
+	}
+
+	// interrupts and exits end the evaluation, and cannot be caught
+	switch unwrapError(err).(type) {
+	case InterruptedError, ExitError:
+		return nil, err
+	}
+
+	errVal := errorValueFromError(err)
+	if len(args) < 2 {
+		return errVal, nil
+	}
+
+	return callFormWithArgs(fr, args[1], []Value{errVal}, node, true)
+}
@@ -7,6 +7,7 @@ import (
 func stringForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
 	if len(args) < 1 {
 		return nil, IncorrectNumberOfArgsError{
+			node:     node,
 			required: 1,
 			given:    len(args),
 		}
@@ -18,6 +19,7 @@ func stringForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError
 func intForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
 	if len(args) < 1 {
 		return nil, IncorrectNumberOfArgsError{
+			node:     node,
 			required: 1,
 			given:    len(args),
 		}
@@ -44,6 +46,7 @@ func intForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
 func fracForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
 	if len(args) < 1 {
 		return nil, IncorrectNumberOfArgsError{
+			node:     node,
 			required: 1,
 			given:    len(args),
 		}
@@ -137,7 +140,27 @@ func typeForm(fr *Frame, args []Value, node *astNode) (Value, InterpreterError)
 			name:   "stream",
 			evaler: streamForm,
 		}, nil
-	case FormValue, NativeFormValue:
+	case ErrorValue:
+		return NativeFormValue{
+			name:   "error",
+			evaler: errorForm,
+		}, nil
+	case ChanValue:
+		return NativeFormValue{
+			name:   "chan",
+			evaler: chanForm,
+		}, nil
+	case FutureValue:
+		return NativeFormValue{
+			name:   "future",
+			evaler: futureForm,
+		}, nil
+	case AstValue:
+		return NativeFormValue{
+			name:   "ast",
+			evaler: astForm,
+		}, nil
+	case FormValue, NativeFormValue, MacroFormValue:
 		return NativeFormValue{
 			name: "form",
 			evaler: func(fr *Frame, args []Value, node *astNode) (Value, InterpreterError) {
@@ -62,10 +62,50 @@ func (v FracValue) Equal(o Value) bool {
 	return false
 }
 
-type argList []string
+// argList describes the parameters of a form definition
+type argList struct {
+	names []string
+	// defaults holds, for each named parameter, the expression
+	// evaluated when the argument is not given, or nil if the
+	// argument is required
+	defaults []*astNode
+	// rest, if not empty, is the name bound to a vec
+	// of any arguments given past the named ones
+	rest string
+}
+
+// required is the number of arguments that must be given to the form.
+// Optional parameters always follow required ones.
+func (al *argList) required() int {
+	for i, d := range al.defaults {
+		if d != nil {
+			return i
+		}
+	}
+	return len(al.names)
+}
+
+func (al *argList) String() string {
+	ss := ""
+	for i, name := range al.names {
+		if d := al.defaults[i]; d != nil {
+			ss += " (" + name + " " + d.String() + ")"
+		} else {
+			ss += " " + name
+		}
+	}
+	if al.rest != "" {
+		ss += " " + restPrefix + al.rest
+	}
+	return ss
+}
 
 type FormValue struct {
 	frame *Frame
+	// name is the name the form was defined with,
+	// used to label the form in stack traces
+	name  string
+	scope *scopeInfo
 	// this level of indirection is to allow FormValue
 	// to be hashable for inclusion in a MapValue
 	arguments  *argList
@@ -73,20 +113,12 @@ type FormValue struct {
 }
 
 func (v FormValue) String() string {
-	ss := ""
-	for _, a := range *v.arguments {
-		ss += " " + a
-	}
-	return "(<form>" + ss + ") " + v.definition.String()
+	return "(<form>" + v.arguments.String() + ") " + v.definition.String()
 }
 
 func (v FormValue) Repr() string {
 	// same impl as FormValue.String()
-	ss := ""
-	for _, a := range *v.arguments {
-		ss += " " + a
-	}
-	return "(<form>" + ss + ") " + v.definition.String()
+	return "(<form>" + v.arguments.String() + ") " + v.definition.String()
 }
 
 func (v FormValue) Equal(o Value) bool {
@@ -97,9 +129,15 @@ func (v FormValue) Equal(o Value) bool {
 	return false
 }
 
+// LazyValue is a deferred invocation of a form. It records the name
+// of the form and the node it was invoked from, so that the call stack
+// can be reconstructed when the value is finally evaluated.
 type LazyValue struct {
 	frame *Frame
 	node  *astNode
+
+	name     string
+	callsite *astNode
 }
 
 func (v LazyValue) String() string {
@@ -118,15 +156,35 @@ func (v LazyValue) Equal(o Value) bool {
 
 func unlazy(v Value) (Value, InterpreterError) {
 	// hot path, a shortcut for frequent case
-	if _, isLazy := v.(LazyValue); !isLazy {
+	lzv, isLazy := v.(LazyValue)
+	if !isLazy {
 		return v, nil
 	}
 
+	vm := lzv.frame.Vm
+	base := vm.stack
+	defer func() {
+		vm.stack = base
+	}()
+
 	var err InterpreterError
-	for lzv, isLazy := v.(LazyValue); isLazy; lzv, isLazy = v.(LazyValue) {
-		v, err = eval(lzv.frame, lzv.node)
+	for ; isLazy; lzv, isLazy = v.(LazyValue) {
+		// a form invoked in tail position replaces the stack record
+		// of its caller, so the stack does not grow with tail calls
+		vm.stack = base
+		vm.pushStack(lzv.name, lzv.callsite, lzv.frame)
+
+		if vm.profile != nil {
+			// each form in a chain of tail calls is
+			// profiled as invoked by the same caller
+			n, start := vm.profile.enter(profFunc{lzv.name, lzv.node})
+			v, err = eval(lzv.frame, lzv.node)
+			vm.profile.exit(n, start)
+		} else {
+			v, err = eval(lzv.frame, lzv.node)
+		}
 		if err != nil {
-			return nil, err
+			return nil, withStackTrace(err, vm.stack)
 		}
 	}
 
@@ -1,52 +1,245 @@
 package xin
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"io"
+	"math/rand"
 	"os"
 	osPath "path"
+	"path/filepath"
+	"reflect"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
-// TODO: implement call stack unwinding on interpreter error
+// stackRecord is one entry in the Xin call stack, recording
+// a single invocation of a form and the node it was called from.
 type stackRecord struct {
 	parent *stackRecord
+	name   string
 	node   *astNode
+	// frame is the frame the invocation is evaluated in, and depth
+	// the number of records in the stack up to and including this one
+	frame *Frame
+	depth int
 }
 
 func (sr stackRecord) String() string {
-	if sr.parent == nil {
-		return fmt.Sprintf("%s at %s",
-			sr.node.String()[0:32],
-			sr.node.position)
+	if sr.node == nil {
+		return sr.name
 	}
 
-	return fmt.Sprintf("%s at %s\nfrom %s",
-		sr.node.String()[0:32],
-		sr.node.position,
-		sr.parent)
+	return fmt.Sprintf("%s at %s", sr.name, sr.node.position)
 }
 
 type Vm struct {
 	Frame *Frame
 
-	stack *stackRecord
+	stack   *stackRecord
+	backend Backend
 	// cached imports
 	imports map[string]*Frame
+	// the directories relative imports resolve from in each file
+	// the Vm has run, which os::chdir changes together
+	cwds []*string
 	// interned native forms
 	evalers map[string]formEvaler
 
+	// ctx is the context of the latest evaluation. Async natives
+	// capture it when scheduled, and drop their callbacks once
+	// it is cancelled.
+	ctx context.Context
+	// interrupted is set atomically when ctx is cancelled,
+	// and checked by the evaluator at every form boundary.
+	// Each evaluation gets its own flag.
+	interrupted *int32
+	// exit records whether the program called os::exit in
+	// the latest evaluation, which it shares with its tasks
+	exit *exitStatus
+
+	// streamIDs counts streams created in this Vm and the Vms of tasks
+	// it spawns, which share streams. It is accessed atomically.
+	streamIDs *int64
+
+	stdin  *bufio.Reader
+	stdout io.Writer
+	stderr io.Writer
+	// stdoutStream is the os::stdout stream, whose pending
+	// writes os::exit waits for before the program exits
+	stdoutStream StreamValue
+	args         []string
+	rand         *rand.Rand
+
+	// profile records the time spent in each form,
+	// while the Vm is being profiled
+	profile *profiler
+	// coverage records the forms evaluated and the branches
+	// taken while the Vm records coverage
+	coverage *coverageRecorder
+	// debug pauses evaluation for a debug client,
+	// while the Vm is being debugged
+	debug *debugger
+	// tests collects the results of test cases
+	// reported through the test library
+	tests *testLog
+
+	sync.Mutex
+	loop *eventLoop
+}
+
+// syncWriter serializes writes to a standard stream of a Vm, which
+// come from both the evaluator and from async stream callbacks.
+type syncWriter struct {
+	lock *sync.Mutex
+	w    io.Writer
+}
+
+func (sw *syncWriter) Write(p []byte) (int, error) {
+	sw.lock.Lock()
+	defer sw.lock.Unlock()
+
+	return sw.w.Write(p)
+}
+
+// exitStatus records the status a program asked to exit with through
+// os::exit, which cancels the rest of its evaluation.
+type exitStatus struct {
 	sync.Mutex
-	waiter sync.WaitGroup
+	cancel context.CancelFunc
+	exited bool
+	code   int
+	// position of the os::exit call
+	position position
+	// abandoned is set if a callback was dropped
+	// because the evaluation was cancelled
+	abandoned bool
+}
+
+// exitStatusKey is the context key of the exitStatus of an evaluation.
+type exitStatusKey struct{}
+
+func newExitStatus(ctx context.Context) (context.Context, *exitStatus) {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &exitStatus{cancel: cancel}
+	return context.WithValue(ctx, exitStatusKey{}, s), s
+}
+
+// abandon records that a callback of the evaluation with the
+// context ctx was dropped, if ctx belongs to an evaluation.
+func abandon(ctx context.Context) {
+	if s, ok := ctx.Value(exitStatusKey{}).(*exitStatus); ok {
+		s.Lock()
+		s.abandoned = true
+		s.Unlock()
+	}
+}
+
+// abandonedCallbacks reports whether any callback
+// of the evaluation was dropped.
+func (s *exitStatus) abandonedCallbacks() bool {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.abandoned
+}
+
+// exitWith records the first exit status the program asks for,
+// and cancels its evaluation.
+func (s *exitStatus) exitWith(code int, pos position) {
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.exited {
+		s.exited = true
+		s.code = code
+		s.position = pos
+	}
+	s.cancel()
+}
+
+// result replaces the result of an evaluation with an ExitError
+// if the program exited, even if it exited in an async callback.
+func (s *exitStatus) result(val *Value, err *InterpreterError) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.exited {
+		*val = nil
+		*err = ExitError{
+			Code:     s.code,
+			position: s.position,
+		}
+	}
+}
+
+// VmOptions configures the environment of a Vm created with
+// NewVmWithOptions. Fields left empty take the defaults of NewVm.
+type VmOptions struct {
+	// Stdin and Stdout back the os::stdin and os::stdout streams and
+	// os::log, and default to the standard input and output of the
+	// process. Stderr receives errors from async callbacks, which
+	// have no caller to return them to, and defaults to standard error.
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// Args is the vec returned by os::args, and defaults to os.Args.
+	Args []string
+	// Seed seeds the random number generator behind math::rand.
+	// If zero, the generator is seeded from the current time.
+	Seed int64
+	// Coverage starts recording coverage before the standard library
+	// is loaded, so that its top level is covered, as if StartCoverage
+	// were called as soon as the Vm was created.
+	Coverage bool
 }
 
 func NewVm() (*Vm, InterpreterError) {
+	return NewVmWithOptions(VmOptions{})
+}
+
+// NewVmWithOptions creates a Vm with its own standard streams, program
+// arguments, and random number generator. Vms share no mutable state,
+// so many can run concurrently in one process.
+func NewVmWithOptions(opts VmOptions) (*Vm, InterpreterError) {
+	if opts.Stdin == nil {
+		opts.Stdin = os.Stdin
+	}
+	if opts.Stdout == nil {
+		opts.Stdout = os.Stdout
+	}
+	if opts.Stderr == nil {
+		opts.Stderr = os.Stderr
+	}
+	if opts.Args == nil {
+		opts.Args = os.Args
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UTC().UnixNano()
+	}
+
+	// stdout and stderr may be the same writer, so share a lock
+	stdioLock := &sync.Mutex{}
 
 	vm := &Vm{
-		Frame:   newFrame(nil), // no parent frame
-		imports: make(map[string]*Frame),
+		imports:     make(map[string]*Frame),
+		ctx:         context.Background(),
+		interrupted: new(int32),
+		exit:        &exitStatus{cancel: func() {}},
+		streamIDs:   new(int64),
+		stdin:       bufio.NewReader(opts.Stdin),
+		stdout:      &syncWriter{lock: stdioLock, w: opts.Stdout},
+		stderr:      &syncWriter{lock: stdioLock, w: opts.Stderr},
+		args:        opts.Args,
+		rand:        rand.New(rand.NewSource(opts.Seed)),
+		tests:       &testLog{},
+	}
+	vm.loop = newEventLoop(vm)
+	if opts.Coverage {
+		vm.coverage = newCoverageRecorder()
 	}
-	vm.Frame.Vm = vm
 
 	cwd, osErr := os.Getwd()
 	if osErr != nil {
@@ -54,22 +247,68 @@ func NewVm() (*Vm, InterpreterError) {
 			reason: "Cannot find working directory",
 		}
 	}
-	vm.Frame.cwd = &cwd
 
-	loadAllDefaultValues(vm)
-	loadAllNativeForms(vm)
-	err := loadStandardLibrary(vm)
+	// builtins and the standard library are bound in their own frame,
+	// above the global frame in which programs bind names
+	std := newFrame(nil) // no parent frame
+	std.Vm = vm
+	std.cwd = &cwd
+	std.shared = true
+	vm.cwds = []*string{&cwd}
+
+	loadAllDefaultValues(vm, std)
+	loadAllNativeForms(vm, std)
+	err := loadStandardLibrary(vm, std)
 	if err != nil {
 		return nil, err
 	}
 
+	vm.Frame = newFrame(std)
 	return vm, nil
 }
 
-func (vm *Vm) pushStack(node *astNode) {
+// NewStream creates a new stream in the Vm, with no callbacks set.
+func (vm *Vm) NewStream() StreamValue {
+	return StreamValue{
+		id:        atomic.AddInt64(vm.streamIDs, 1),
+		callbacks: &streamCallbacks{},
+	}
+}
+
+// reportError prints and keeps an error from an async
+// callback, which has no caller to return the error to.
+// Exits are not errors, and end the evaluation instead.
+func (vm *Vm) reportError(err InterpreterError) {
+	if _, ok := unwrapError(err).(ExitError); ok {
+		return
+	}
+
+	vm.loop.reportError(err)
+	fmt.Fprintln(vm.stderr, FormatError(err))
+}
+
+// SetBackend sets the evaluator used to run programs in this VM.
+// The standard library is always loaded with the tree-walking
+// evaluator, but forms defined in it run on the chosen backend.
+func (vm *Vm) SetBackend(b Backend) {
+	vm.Lock()
+	defer vm.Unlock()
+
+	vm.backend = b
+}
+
+func (vm *Vm) pushStack(name string, node *astNode, frame *Frame) {
+	depth := 1
+	if vm.stack != nil {
+		depth = vm.stack.depth + 1
+	}
+
 	vm.stack = &stackRecord{
-		parent: vm.stack.parent,
+		parent: vm.stack,
+		name:   name,
 		node:   node,
+		frame:  frame,
+		depth:  depth,
 	}
 }
 
@@ -82,7 +321,33 @@ func (vm *Vm) popStack() {
 }
 
 func (vm *Vm) Eval(path string, r io.Reader) (Value, InterpreterError) {
-	defer vm.waiter.Wait()
+	return vm.EvalContext(context.Background(), path, r)
+}
+
+// EvalContext evaluates a Xin program like Eval, but stops evaluation
+// at the next form boundary once ctx is cancelled, and abandons any async
+// callbacks (os::wait, ->, <-, os::listen) still pending from the program.
+// This can be used to interrupt runaway programs or bound evaluation time
+// with context.WithTimeout. Either way, it returns an InterruptedError.
+//
+// If the program calls os::exit, evaluation stops and EvalContext returns
+// an ExitError with the program's exit status.
+func (vm *Vm) EvalContext(ctx context.Context, path string, r io.Reader) (val Value, err InterpreterError) {
+	ctx, exit := newExitStatus(ctx)
+	defer exit.result(&val, &err)
+
+	interrupted := new(int32)
+	stop := make(chan struct{})
+	go watch(ctx, interrupted, stop)
+	defer close(stop)
+	defer func() {
+		// callbacks abandoned when ctx is cancelled
+		// are reported as an interrupted evaluation
+		drained := vm.wait(ctx, interrupted)
+		if (!drained || exit.abandonedCallbacks()) && err == nil {
+			val, err = nil, InterruptedError{}
+		}
+	}()
 
 	toks, err := lex(path, r)
 	if err != nil {
@@ -92,19 +357,63 @@ func (vm *Vm) Eval(path string, r io.Reader) (Value, InterpreterError) {
 	if err != nil {
 		return nil, err
 	}
+	resolveNames(&rootNode, nil)
 
 	vm.Lock()
 	defer vm.Unlock()
 
-	val, err := unlazyEval(vm.Frame, &rootNode)
+	vm.ctx = ctx
+	vm.interrupted = interrupted
+	vm.exit = exit
+
+	val, err = unlazyEval(vm.Frame, &rootNode)
 	if err != nil {
-		return nil, err
+		return nil, withStackTrace(err, vm.stack)
 	}
 
 	return val, nil
 }
 
+// watch raises the interrupted flag when ctx is cancelled,
+// until the evaluation it belongs to has finished.
+func watch(ctx context.Context, interrupted *int32, stop <-chan struct{}) {
+	select {
+	case <-ctx.Done():
+		atomic.StoreInt32(interrupted, 1)
+	case <-stop:
+	}
+}
+
+// wait blocks until all pending async operations are done, or until
+// ctx is cancelled. In the latter case, it also waits for any callback
+// that is currently running to be interrupted, so the VM can be reused.
+// It reports whether every pending operation finished.
+func (vm *Vm) wait(ctx context.Context, interrupted *int32) bool {
+	drained := vm.loop.drained()
+	select {
+	case <-drained:
+		return true
+	case <-ctx.Done():
+		atomic.StoreInt32(interrupted, 1)
+		vm.Lock()
+		vm.Unlock()
+	}
+
+	select {
+	case <-drained:
+		return true
+	default:
+		return false
+	}
+}
+
 func (vm *Vm) Exec(path string) InterpreterError {
+	return vm.ExecContext(context.Background(), path)
+}
+
+// ExecContext runs a Xin program file like Exec, with the
+// cancellation behavior of EvalContext.
+func (vm *Vm) ExecContext(ctx context.Context, path string) InterpreterError {
 	file, err := os.Open(path)
 	defer file.Close()
 	if err != nil {
@@ -113,12 +422,158 @@ func (vm *Vm) Exec(path string) InterpreterError {
 		}
 	}
 
-	cwd := osPath.Dir(path)
+	// the directory is absolute, so that it still names the same
+	// directory if another Vm changes the working directory
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return RuntimeError{
+			reason: fmt.Sprintf("Error opening file: %s", err),
+		}
+	}
+	cwd := osPath.Dir(absPath)
 	vm.Frame.cwd = &cwd
-	_, ierr := vm.Eval(path, file)
+	vm.cwds = append(vm.cwds, &cwd)
+	_, ierr := vm.EvalContext(ctx, path, file)
 	if ierr != nil {
 		return ierr
 	}
 
 	return nil
 }
+
+// Get returns the value bound to name in the Vm's global frame,
+// which holds the top-level bindings of programs run with Eval and
+// Exec, and reports whether the name is bound.
+func (vm *Vm) Get(name string) (Value, bool) {
+	vm.Lock()
+	defer vm.Unlock()
+
+	val, err := vm.Frame.Get(name, position{})
+	if err != nil {
+		return nil, false
+	}
+	return val, true
+}
+
+// Call invokes a Xin form with the given arguments, like CallContext
+// without a deadline. The result is returned as a Xin Value, because a
+// form may return a value with no Go equivalent, like a stream or another
+// form, that the caller can pass back into the Vm. Use FromValue to
+// convert the result, or CallInto to convert it to a particular type.
+func (vm *Vm) Call(form Value, args ...interface{}) (Value, InterpreterError) {
+	return vm.CallContext(context.Background(), form, args...)
+}
+
+// CallContext invokes a Xin form with the given arguments and returns
+// its fully evaluated result. Arguments are converted to Xin values
+// with ToValue, and the result can be converted back with FromValue.
+// Like EvalContext, it waits for any async callbacks the form schedules,
+// is interrupted when ctx is cancelled, and returns an ExitError if the
+// form calls os::exit.
+//
+// CallContext takes the Vm lock, so it must not be called from within
+// a native form while the Vm is evaluating.
+func (vm *Vm) CallContext(ctx context.Context, form Value, args ...interface{}) (val Value, err InterpreterError) {
+	argValues := make([]Value, len(args))
+	for i, arg := range args {
+		val, err := ToValue(arg)
+		if err != nil {
+			return nil, RuntimeError{
+				reason: err.Error(),
+			}
+		}
+		argValues[i] = val
+	}
+
+	ctx, exit := newExitStatus(ctx)
+	defer exit.result(&val, &err)
+
+	interrupted := new(int32)
+	stop := make(chan struct{})
+	go watch(ctx, interrupted, stop)
+	defer close(stop)
+	defer func() {
+		// callbacks abandoned when ctx is cancelled
+		// are reported as an interrupted evaluation
+		drained := vm.wait(ctx, interrupted)
+		if (!drained || exit.abandonedCallbacks()) && err == nil {
+			val, err = nil, InterruptedError{}
+		}
+	}()
+
+	vm.Lock()
+	defer vm.Unlock()
+
+	vm.ctx = ctx
+	vm.interrupted = interrupted
+	vm.exit = exit
+
+	val, err = unlazyEvalFormWithArgs(vm.Frame, form, argValues, hostCallNode(form, argValues))
+	if err != nil {
+		return nil, withStackTrace(err, vm.stack)
+	}
+
+	return val, nil
+}
+
+// CallInto invokes a Xin form like Call, and stores its result in the
+// value dst points to, converting it to the type of *dst the way
+// RegisterFunc converts arguments. It returns an error if dst is not a
+// non-nil pointer or the result does not fit the type of *dst.
+func (vm *Vm) CallInto(dst interface{}, form Value, args ...interface{}) InterpreterError {
+	ptr := reflect.ValueOf(dst)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+		return RuntimeError{
+			reason: fmt.Sprintf("cannot store the result of a call in %T", dst),
+		}
+	}
+
+	val, err := vm.Call(form, args...)
+	if err != nil {
+		return err
+	}
+
+	result, convErr := fromValueTo(val, ptr.Elem().Type())
+	if convErr != nil {
+		return RuntimeError{
+			reason: convErr.Error(),
+		}
+	}
+	ptr.Elem().Set(result)
+
+	return nil
+}
+
+// hostCallNode synthesizes the invocation of a form called from Go,
+// for use in error messages and stack traces.
+func hostCallNode(form Value, args []Value) *astNode {
+	pos := position{path: "(host)"}
+
+	name := "(host call)"
+	switch f := form.(type) {
+	case FormValue:
+		if f.name != "" {
+			name = f.name
+		}
+	case NativeFormValue:
+		name = f.name
+	}
+
+	leaves := []*astNode{{
+		token: token{
+			kind:     tkName,
+			value:    name,
+			position: pos,
+		},
+		position: pos,
+	}}
+	for _, arg := range args {
+		leaves = append(leaves, valueLiteralNode(arg))
+	}
+
+	return &astNode{
+		isForm:   true,
+		leaves:   leaves,
+		position: pos,
+	}
+}
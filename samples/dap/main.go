@@ -0,0 +1,259 @@
+// dap runs a scripted session against the Xin debug adapter, started
+// with the xin binary given as its argument. It debugs program.xin in
+// this directory, checking breakpoints, stack traces, variables,
+// stepping and continuing, and exits with an error if any check fails.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var failed = false
+
+func check(desc string, got, expected interface{}) {
+	if reflect.DeepEqual(got, expected) {
+		fmt.Printf("ok\t%s\n", desc)
+		return
+	}
+
+	fmt.Printf("FAIL\t%s\n\texpected %#v but got %#v\n", desc, expected, got)
+	failed = true
+}
+
+// message is a DAP message, decoded loosely
+// so that any part of it can be checked
+type message map[string]interface{}
+
+// get follows a path of object keys and array indexes into a message
+func (m message) get(path ...interface{}) interface{} {
+	var v interface{} = map[string]interface{}(m)
+	for _, key := range path {
+		switch k := key.(type) {
+		case string:
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			v = obj[k]
+		case int:
+			arr, ok := v.([]interface{})
+			if !ok || k >= len(arr) {
+				return nil
+			}
+			v = arr[k]
+		}
+	}
+	return v
+}
+
+// client speaks the Debug Adapter Protocol to the adapter over its stdio
+type client struct {
+	w   io.Writer
+	r   *textproto.Reader
+	seq int
+	// events received while waiting for responses
+	events []message
+	// output is the output of the debugged program so far
+	output strings.Builder
+}
+
+func (c *client) read() message {
+	header, err := c.r.ReadMIMEHeader()
+	if err != nil {
+		fmt.Println("Error reading from the debug adapter:", err)
+		os.Exit(1)
+	}
+	length, _ := strconv.Atoi(header.Get("Content-Length"))
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r.R, body); err != nil {
+		fmt.Println("Error reading from the debug adapter:", err)
+		os.Exit(1)
+	}
+
+	var msg message
+	json.Unmarshal(body, &msg)
+	if msg["event"] == "output" {
+		out, _ := msg.get("body", "output").(string)
+		c.output.WriteString(out)
+	}
+	return msg
+}
+
+// request sends a request, and returns its response once it arrives
+func (c *client) request(command string, args interface{}) message {
+	c.seq++
+	body, _ := json.Marshal(message{
+		"seq":       c.seq,
+		"type":      "request",
+		"command":   command,
+		"arguments": args,
+	})
+	fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(body), body)
+
+	for {
+		msg := c.read()
+		if seq, ok := msg["request_seq"].(float64); ok && int(seq) == c.seq {
+			return msg
+		}
+		c.events = append(c.events, msg)
+	}
+}
+
+// event waits for the next event with the given name
+func (c *client) event(name string) message {
+	for {
+		var msg message
+		if len(c.events) > 0 {
+			msg, c.events = c.events[0], c.events[1:]
+		} else {
+			msg = c.read()
+		}
+		if msg["event"] == name {
+			return msg
+		}
+	}
+}
+
+// top returns the name and line of the innermost stack frame
+func (c *client) top() (interface{}, interface{}) {
+	trace := c.request("stackTrace", message{"threadId": 1})
+	return trace.get("body", "stackFrames", 0, "name"), trace.get("body", "stackFrames", 0, "line")
+}
+
+// variables lists the variables under a reference by name
+func (c *client) variables(ref interface{}) map[string]string {
+	resp := c.request("variables", message{"variablesReference": ref})
+	vars := map[string]string{}
+	items, _ := resp.get("body", "variables").([]interface{})
+	for _, item := range items {
+		v := item.(map[string]interface{})
+		vars[v["name"].(string)] = v["value"].(string)
+	}
+	return vars
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("Usage: dap path/to/xin")
+		os.Exit(1)
+	}
+
+	program, _ := filepath.Abs("samples/dap/program.xin")
+
+	cmd := exec.Command(os.Args[1], "debug")
+	cmd.Stderr = os.Stderr
+	stdin, _ := cmd.StdinPipe()
+	stdout, _ := cmd.StdoutPipe()
+	if err := cmd.Start(); err != nil {
+		fmt.Println("Error starting the debug adapter:", err)
+		os.Exit(1)
+	}
+	// a session that hangs is a failure too
+	timer := time.AfterFunc(30*time.Second, func() {
+		fmt.Println("FAIL\tthe debug session timed out")
+		cmd.Process.Kill()
+		os.Exit(1)
+	})
+	defer timer.Stop()
+
+	c := &client{
+		w: stdin,
+		r: textproto.NewReader(bufio.NewReader(stdout)),
+	}
+
+	init := c.request("initialize", message{"adapterID": "xin"})
+	check("initialize", init.get("body", "supportsConfigurationDoneRequest"), true)
+	c.event("initialized")
+
+	launch := c.request("launch", message{"program": program})
+	check("launch", launch["success"], true)
+
+	// breakpoints
+	bps := c.request("setBreakpoints", message{
+		"source":      message{"path": program},
+		"breakpoints": []message{{"line": 4}},
+	})
+	check("setBreakpoints", bps.get("body", "breakpoints", 0, "verified"), true)
+	c.request("configurationDone", nil)
+
+	stopped := c.event("stopped")
+	check("stops at breakpoints", stopped.get("body", "reason"), "breakpoint")
+	name, line := c.top()
+	check("stack trace names the form", name, "square")
+	check("stack trace has the line of the breakpoint", line, 4.0)
+	trace := c.request("stackTrace", message{"threadId": 1})
+	check("stack trace has the source",
+		trace.get("body", "stackFrames", 0, "source", "path"), program)
+	callers := []interface{}{}
+	for _, f := range trace.get("body", "stackFrames").([]interface{}) {
+		callers = append(callers, f.(map[string]interface{})["name"])
+	}
+	check("stack trace has the callers",
+		callers[len(callers)-2:], []interface{}{"sum-squares", "(top level)"})
+
+	// variables
+	scopes := c.request("scopes", message{"frameId": 1})
+	check("local scope", scopes.get("body", "scopes", 0, "name"), "Locals")
+	locals := c.variables(scopes.get("body", "scopes", 0, "variablesReference"))
+	check("local variables", locals["n"], "1")
+
+	globalsRef := scopes.get("body", "scopes", len(scopes.get("body", "scopes").([]interface{}))-1, "variablesReference")
+	globals := c.variables(globalsRef)
+	check("global variables", globals["numbers"], "(<vec> 1 2 3)")
+	globalsResp := c.request("variables", message{"variablesReference": globalsRef})
+	var numbersRef interface{}
+	for _, item := range globalsResp.get("body", "variables").([]interface{}) {
+		v := item.(map[string]interface{})
+		if v["name"] == "numbers" {
+			numbersRef = v["variablesReference"]
+		}
+	}
+	check("vecs expand into their items", len(c.variables(numbersRef)), 3)
+
+	eval := c.request("evaluate", message{"expression": "(+ n 10)", "frameId": 1})
+	check("evaluate in a stack frame", eval.get("body", "result"), "11")
+
+	// stepping, after the breakpoint is cleared
+	c.request("setBreakpoints", message{
+		"source":      message{"path": program},
+		"breakpoints": []message{},
+	})
+	c.request("stepOut", message{"threadId": 1})
+	stopped = c.event("stopped")
+	check("stepOut stops", stopped.get("body", "reason"), "step")
+	name, line = c.top()
+	check("stepOut returns to the caller", name, "sum-squares")
+	check("stepOut continues after the call", line, 9.0)
+
+	c.request("next", message{"threadId": 1})
+	stopped = c.event("stopped")
+	check("next stops", stopped.get("body", "reason"), "step")
+	name, line = c.top()
+	check("next steps over the rest of the form", name, "(top level)")
+	check("next stops on the next line", line, 13.0)
+
+	// continuing to the end
+	c.request("continue", message{"threadId": 1})
+	exited := c.event("exited")
+	check("the program exits", exited.get("body", "exitCode"), 0.0)
+	c.event("terminated")
+	check("the program's output is sent to the client", c.output.String(), "14\ndone\n")
+
+	c.request("disconnect", nil)
+	check("disconnect", cmd.Wait(), nil)
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,182 @@
+// embed is a Go program that embeds Xin, exposing Go functions to Xin
+// as native forms and calling Xin forms from Go. It checks the values
+// passed between them, and exits with an error if any check fails.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/thesephist/xin/pkg/xin"
+)
+
+var failed = false
+
+func check(desc string, got, expected interface{}) {
+	if reflect.DeepEqual(got, expected) {
+		fmt.Printf("ok\t%s\n", desc)
+		return
+	}
+
+	fmt.Printf("FAIL\t%s\n\texpected %#v but got %#v\n", desc, expected, got)
+	failed = true
+}
+
+// eval evaluates a Xin expression, and converts its value to Go
+func eval(vm *xin.Vm, src string) interface{} {
+	val, err := vm.Eval("embed", strings.NewReader(src))
+	if err != nil {
+		return xin.FormatError(err)
+	}
+	return xin.FromValue(val)
+}
+
+func main() {
+	vm, ierr := xin.NewVm()
+	if ierr != nil {
+		fmt.Println(xin.FormatError(ierr))
+		os.Exit(1)
+	}
+
+	vm.RegisterForm("app::greet", func(fr *xin.Frame, args []xin.Value) (xin.Value, error) {
+		if len(args) < 1 {
+			return nil, xin.NewArgCountError(1, len(args))
+		}
+		name, ok := args[0].(xin.StringValue)
+		if !ok {
+			return nil, xin.NewArgumentError(args)
+		}
+		return xin.StringValue("Hello, " + string(name)), nil
+	})
+	vm.RegisterForm("app::fail", func(fr *xin.Frame, args []xin.Value) (xin.Value, error) {
+		return nil, xin.NewRuntimeError("app failed")
+	})
+	vm.RegisterForm("app::lookup", func(fr *xin.Frame, args []xin.Value) (xin.Value, error) {
+		return xin.NewErrorValue("no such user", "not-found"), nil
+	})
+
+	must := func(err error) {
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+	must(vm.RegisterFunc("app::upper", strings.ToUpper))
+	must(vm.RegisterFunc("app::sum", func(ns []int) int {
+		total := 0
+		for _, n := range ns {
+			total += n
+		}
+		return total
+	}))
+	must(vm.RegisterFunc("app::div", func(a, b int) (int, error) {
+		if b == 0 {
+			return 0, errors.New("division by zero")
+		}
+		return a / b, nil
+	}))
+	must(vm.RegisterFunc("app::byte", func(b uint8) uint8 {
+		return b
+	}))
+	must(vm.RegisterFunc("app::counter", func() func() int {
+		n := 0
+		return func() int {
+			n++
+			return n
+		}
+	}))
+
+	// native forms
+	check("RegisterForm", eval(vm, "(app::greet 'xin')"), "Hello, xin")
+	check("RegisterFunc", eval(vm, "(app::greet (app::upper 'xin'))"), "Hello, XIN")
+	check("RegisterFunc with a slice", eval(vm, "(app::sum (vec 1 2 3))"), int64(6))
+	check("RegisterFunc with an error result", eval(vm, "(app::div 7 2)"), int64(3))
+
+	// errors
+	kind := func(src string) interface{} {
+		return eval(vm, "(try (: (f) "+src+") error::kind)")
+	}
+	check("NewArgCountError", kind("(app::greet)"), "arity")
+	check("NewArgumentError", kind("(app::greet 42)"), "mismatched-args")
+	check("NewRuntimeError", kind("(app::fail)"), "runtime")
+	check("NewErrorValue", eval(vm, "(error::kind (app::lookup 'linus'))"), "not-found")
+	check("error results are raised", eval(vm, "(try (: (f) (app::div 1 0)) error::message)"), "Runtime error: division by zero")
+
+	// argument conversions
+	check("whole fracs convert to ints", eval(vm, "(app::div 7.0 2)"), int64(3))
+	check("fracs do not truncate to ints", kind("(app::div 7.5 2)"), "mismatched-args")
+	check("negative ints do not convert to uints", kind("(app::byte -1)"), "mismatched-args")
+	check("ints do not overflow", kind("(app::byte 256)"), "mismatched-args")
+
+	// converted funcs are distinct forms
+	check("converted funcs keep their state",
+		eval(vm, "(do (: c (app::counter)) (c) (c))"), int64(2))
+	check("converted funcs are distinct map keys",
+		eval(vm, "(do (: m (map)) (map::set! m (app::counter) 1) (map::set! m (app::counter) 2) (map::size m))"),
+		int64(2))
+	check("converted funcs as map keys can be called",
+		eval(vm, "(do (: m (map)) (map::set! m (app::counter) 1) ((vec::head (map::keys m))))"),
+		int64(1))
+
+	// round trips
+	for _, v := range []interface{}{
+		int64(42),
+		3.5,
+		"xin",
+		[]interface{}{int64(1), "two", []interface{}{3.0}},
+		map[string]interface{}{"a": int64(1), "b": []interface{}{"c"}},
+	} {
+		val, err := xin.ToValue(v)
+		if err != nil {
+			check(fmt.Sprintf("ToValue %#v", v), err.Error(), nil)
+			continue
+		}
+		check(fmt.Sprintf("round trip of %#v", v), xin.FromValue(val), v)
+	}
+	_, err := xin.ToValue(make(chan int))
+	check("ToValue of an unsupported type", err != nil, true)
+	_, err = xin.ToValue(uint64(math.MaxInt64) + 1)
+	check("ToValue of a uint that overflows an int", err != nil, true)
+	val, _ := xin.ToValue(uint64(math.MaxInt64))
+	check("ToValue of the largest uint that fits an int", xin.FromValue(val), int64(math.MaxInt64))
+
+	// calling Xin forms from Go
+	vm.Eval("plugin", strings.NewReader("(: (on-event name) (+ 'handled ' name))"))
+	handler, _ := vm.Get("on-event")
+	result, ierr := vm.Call(handler, "click")
+	if ierr != nil {
+		check("Call", xin.FormatError(ierr), nil)
+	} else {
+		check("Call", xin.FromValue(result), "handled click")
+	}
+
+	var reply string
+	ierr = vm.CallInto(&reply, handler, "tap")
+	check("CallInto", reply, "handled tap")
+	check("CallInto without an error", ierr, nil)
+
+	var count int
+	ierr = vm.CallInto(&count, handler, "tap")
+	check("CallInto a mismatched type", ierr != nil, true)
+	ierr = vm.CallInto(count, handler, "tap")
+	check("CallInto a non-pointer", ierr != nil, true)
+
+	// bounding evaluation time
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	_, ierr = vm.EvalContext(ctx, "timeout", strings.NewReader("(os::wait 5 (: (f) (log 'late')))"))
+	cancel()
+	_, interrupted := ierr.(xin.InterruptedError)
+	check("EvalContext reports abandoned callbacks", interrupted, true)
+	_, ierr = vm.EvalContext(context.Background(), "after", strings.NewReader("(os::wait 0.01)"))
+	check("EvalContext after a timeout", ierr, nil)
+
+	if failed {
+		os.Exit(1)
+	}
+}
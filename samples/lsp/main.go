@@ -0,0 +1,261 @@
+// lsp runs a scripted session against the Xin language server, started
+// with the xin binary given as its argument. It opens a document that
+// imports shapes.xin from this directory, checks the diagnostics,
+// definitions, hovers and completions the server returns, and exits
+// with an error if any check fails.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var failed = false
+
+func check(desc string, got, expected interface{}) {
+	if reflect.DeepEqual(got, expected) {
+		fmt.Printf("ok\t%s\n", desc)
+		return
+	}
+
+	fmt.Printf("FAIL\t%s\n\texpected %#v but got %#v\n", desc, expected, got)
+	failed = true
+}
+
+// message is a JSON-RPC message, decoded loosely
+// so that any part of it can be checked
+type message map[string]interface{}
+
+// get follows a path of object keys and array indexes into a message
+func (m message) get(path ...interface{}) interface{} {
+	var v interface{} = map[string]interface{}(m)
+	for _, key := range path {
+		switch k := key.(type) {
+		case string:
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			v = obj[k]
+		case int:
+			arr, ok := v.([]interface{})
+			if !ok || k >= len(arr) {
+				return nil
+			}
+			v = arr[k]
+		}
+	}
+	return v
+}
+
+// client speaks JSON-RPC to the language server over its stdio
+type client struct {
+	w      io.Writer
+	r      *textproto.Reader
+	nextID int
+	// notifications received while waiting for responses
+	notifications []message
+}
+
+func (c *client) send(msg message) {
+	msg["jsonrpc"] = "2.0"
+	body, _ := json.Marshal(msg)
+	fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func (c *client) read() message {
+	header, err := c.r.ReadMIMEHeader()
+	if err != nil {
+		fmt.Println("Error reading from the language server:", err)
+		os.Exit(1)
+	}
+	length, _ := strconv.Atoi(header.Get("Content-Length"))
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r.R, body); err != nil {
+		fmt.Println("Error reading from the language server:", err)
+		os.Exit(1)
+	}
+
+	var msg message
+	json.Unmarshal(body, &msg)
+	return msg
+}
+
+func (c *client) notify(method string, params interface{}) {
+	c.send(message{"method": method, "params": params})
+}
+
+// request sends a request, and returns its response once it arrives
+func (c *client) request(method string, params interface{}) message {
+	c.nextID++
+	c.send(message{"id": c.nextID, "method": method, "params": params})
+
+	for {
+		msg := c.read()
+		if id, ok := msg["id"].(float64); ok && int(id) == c.nextID {
+			return msg
+		}
+		c.notifications = append(c.notifications, msg)
+	}
+}
+
+// diagnostics waits for the next diagnostics published for a document
+func (c *client) diagnostics(uri string) []interface{} {
+	for {
+		var msg message
+		if len(c.notifications) > 0 {
+			msg, c.notifications = c.notifications[0], c.notifications[1:]
+		} else {
+			msg = c.read()
+		}
+		if msg["method"] == "textDocument/publishDiagnostics" && msg.get("params", "uri") == uri {
+			diagnostics, _ := msg.get("params", "diagnostics").([]interface{})
+			return diagnostics
+		}
+	}
+}
+
+func position(uri string, line, character int) message {
+	return message{
+		"textDocument": message{"uri": uri},
+		"position":     message{"line": line, "character": character},
+	}
+}
+
+const document = `(import 'shapes' shapes)
+(import 'missing')
+
+; twice the area of a rectangle
+(: (double-area w h)
+   (* 2 (shapes::area w h)))
+
+(log (double-area 2 3))
+(log undefined-name)
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("Usage: lsp path/to/xin")
+		os.Exit(1)
+	}
+
+	dir, _ := filepath.Abs("samples/lsp")
+	uri := "file://" + filepath.Join(dir, "main.xin")
+	shapesURI := "file://" + filepath.Join(dir, "shapes.xin")
+
+	cmd := exec.Command(os.Args[1], "lsp")
+	cmd.Stderr = os.Stderr
+	stdin, _ := cmd.StdinPipe()
+	stdout, _ := cmd.StdoutPipe()
+	if err := cmd.Start(); err != nil {
+		fmt.Println("Error starting the language server:", err)
+		os.Exit(1)
+	}
+	c := &client{
+		w: stdin,
+		r: textproto.NewReader(bufio.NewReader(stdout)),
+	}
+
+	init := c.request("initialize", message{"capabilities": message{}})
+	check("initialize", init.get("result", "capabilities", "hoverProvider"), true)
+	c.notify("initialized", message{})
+
+	// diagnostics
+	c.notify("textDocument/didOpen", message{
+		"textDocument": message{
+			"uri":        uri,
+			"languageId": "xin",
+			"version":    1,
+			"text":       document,
+		},
+	})
+	diagnostics := c.diagnostics(uri)
+	check("diagnostics", len(diagnostics), 2)
+	check("diagnostics for undefined names",
+		message{"d": diagnostics}.get("d", 0, "message"), "Undefined name undefined-name")
+	check("diagnostics are ranged over the name",
+		message{"d": diagnostics}.get("d", 0, "range"),
+		map[string]interface{}{
+			"start": map[string]interface{}{"line": 8.0, "character": 5.0},
+			"end":   map[string]interface{}{"line": 8.0, "character": 19.0},
+		})
+	check("diagnostics for missing imports",
+		message{"d": diagnostics}.get("d", 1, "message"),
+		"Could not open imported file "+filepath.Join(dir, "missing.xin"))
+
+	// definitions
+	def := c.request("textDocument/definition", position(uri, 7, 8))
+	check("definition in the document", def.get("result", "uri"), uri)
+	check("definition in the document is its binding", def.get("result", "range", "start", "line"), 4.0)
+	def = c.request("textDocument/definition", position(uri, 5, 12))
+	check("definition in an import", def.get("result", "uri"), shapesURI)
+	check("definition in an import is its binding", def.get("result", "range", "start", "line"), 3.0)
+	def = c.request("textDocument/definition", position(uri, 0, 11))
+	check("definition of an import path", def.get("result", "uri"), shapesURI)
+
+	// hovers
+	hover := c.request("textDocument/hover", position(uri, 7, 8))
+	check("hover shows the signature and comment",
+		hover.get("result", "contents", "value"),
+		"```xin\n(double-area w h)\n```\n\ntwice the area of a rectangle")
+	hover = c.request("textDocument/hover", position(uri, 5, 12))
+	value, _ := hover.get("result", "contents", "value").(string)
+	check("hover in an import", strings.Contains(value, "area of a rectangle"), true)
+	hover = c.request("textDocument/hover", position(uri, 5, 4))
+	check("hover on native forms", hover.get("result", "contents", "value"), "```xin\n*\n```\n\nNative form")
+	hover = c.request("textDocument/hover", position(uri, 3, 4))
+	check("hover on a comment", hover.get("result"), nil)
+
+	// completions, from the latest version of the document that parsed
+	c.notify("textDocument/didChange", message{
+		"textDocument":   message{"uri": uri, "version": 2},
+		"contentChanges": []message{{"text": document + "(log (double-"}},
+	})
+	diagnostics = c.diagnostics(uri)
+	check("diagnostics for syntax errors", len(diagnostics), 1)
+
+	labels := func(result message) []string {
+		items, _ := result.get("result").([]interface{})
+		labels := []string{}
+		for _, item := range items {
+			labels = append(labels, item.(map[string]interface{})["label"].(string))
+		}
+		return labels
+	}
+	completion := c.request("textDocument/completion", position(uri, 9, 13))
+	check("completion of names in the document", labels(completion), []string{"double-area"})
+	check("completions replace the name being typed",
+		completion.get("result", 0, "textEdit", "range", "start", "character"), 6.0)
+
+	c.notify("textDocument/didChange", message{
+		"textDocument":   message{"uri": uri, "version": 3},
+		"contentChanges": []message{{"text": document + "(shapes::"}},
+	})
+	c.diagnostics(uri)
+	completion = c.request("textDocument/completion", position(uri, 9, 8))
+	check("completion of imported names", labels(completion), []string{"shapes::area"})
+
+	c.notify("textDocument/didClose", message{"textDocument": message{"uri": uri}})
+	check("closing a document clears its diagnostics", len(c.diagnostics(uri)), 0)
+
+	// unknown requests and shutdown
+	unknown := c.request("textDocument/rename", position(uri, 0, 0))
+	check("unsupported methods", unknown.get("error", "code"), -32601.0)
+	shutdown := c.request("shutdown", nil)
+	check("shutdown", shutdown.get("error"), nil)
+	c.notify("exit", nil)
+	check("exit", cmd.Wait(), nil)
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -8,7 +8,7 @@ import (
 
 
 func init() {
-	data := "PK\x03\x04\x14\x00\x08\x00\x08\x00\x0de[P\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x07\x00	\x00map.xinUT\x05\x00\x01\xbb\xb8W^\xac\x8f\xc1j\xc4 \x14E\xf7\xf9\x8a\xbb*\xba\x08tm)\xf9\x16k^K\xd0\x17\xacq\x04\xe7\xeb\x07\xa3\x19B2\x03\xb3\x98\xb7\xf3r.\xe7\xfa\x05\xd6\x1eK\xd4\xf3\xa8\xc3\x087\xfd\x04\x1dr\xd7	\x05A\xecc\x1e\xc0\xb2\x03 \xbe!X{\xa5\x96\xe9J`\x89O)\x1b\xf6?\x80\xe1*\xf5q\x06w/'W\xaa\x80\x89\x8cR\x94(\xe4\x96lY\xd9S+\x96\xf2\xd2\xec\x87+Z\xc3\xbeO\xda\x81{K\xf9\x11\xb4\x1f\xfdG\x11\xdc\xd05\xeaK\xb2\x95\xeb\xd5\xdf$\xed.\xb4i_ZTZ\xbf\xb0\xe7	{\xb5\x95\xc7V\"s\x97\xd2\x1c\xc3\xf4.k\"\x03{\x94?\xb1\xdf\x02\x00\x00\xff\xffPK\x07\x08.\xc1\xc1\xd4\xb2\x00\x00\x00\xff\x01\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x87\xb8bP\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x08\x00	\x00math.xinUT\x05\x00\x01\xee\x90]^\x84T\xcdr\xdb<\x0c\xbc\xfb)\xf6;|S1\x89\xeb$3\xbd\xd8\xf99\xb4\x9d>\x07$Q2;\xfcQH*i\xfa\xf4\x1d\x90\xfa\xb1\x14\xbb\xe5\xc13\x02\x17\x8b\xc5\x82\xc6\x01\x86\xe2Q\x1a\x8a\xaa\n\xd0\xaa\xf4\xe4\xdf7\x9bb\x8f\x82\xca\x00+6\x00\n\xd5\xa0x\x80\xc5\xad@ae\x0b+`\x85H\xb0N%\xc4\x15\xeeQ0\xd5~OAY\xdc	\xbe?\xe0{_iU\x7f\n\xf8\xf1\xf5\x1bH\xb7\xce\xabx4\x89\xbf\xadj\x10\xcaT\xe1\x80\xceK/_\xf6 <\xa0L\x94\xb5\x03\xc3B_N\xb0\xe1$=\x8f \xdc\x9eF\x91\xf3f\x14g\x16\xff\xa3\x04	\x10\xeb\xc9a\xae\x91\x9a\xa3\x93P\x99C\xe5\x14\xe2\x12O\x8b\xc2\x89\x8f\xc9\x16\x01F\x0c\xbdjI!\xa2r\xc68\x0b\xd3\xeb\xa8:-\xd1\x07e[\xb4U\x9d\x9a\xd6\x95\x99H\x8b+V\xb2\xe3\xda\xa3\x17bp\xb5\xe8\xbc2\xf2y\xed\xff\xfdP\xbb!\x1d\xe4 4\xdbd\xe8\x17\ne+\xfe\x89(\xc2\x8b\x8f<\"1\x8b\x1d\xbdT\x0b\xcf\x06+\x15\x13,\xcdD\xf4\xbd\\F\x12\xb8\xa1*:\xff\x0c\xbbb\xc2R\xd7IV\x9a\x03kS,H\xe0~4,5\x89L\xa8~KX\x8a\xbd'\x0d\xdb\x9bR\xfa\xd9\x87m\x86L\xcfql\xa5k\x02\x0c\xbafn2wcpw\"\xadk\xc2\xfc\xb1ha\x91;K}\x95\xd5~Ou\xfd_*\xb0\x82d\xdc\xeecn:\xe7\xf8\x16\xf5\x87c>R\xb2A]#\xa6\x99\xb1\x0c\x91f\x9e\xbc\xba\xe8\xd2\x01\x8d\xf3\xd0\xe4[	{\x03C\xef(%\x8c\xf3\x12\xb2iT\xa5\xa4\x8d\x88\x0e\x8d\xf2!\x8e\xa6og:\xb25:\xef\xea\xbe\x92\xfcvKe)*g\x03\\\xb3\x18PH\xf3\xb80	\xed\xde\xa4G\xdfu\xd2\xc3/\xc7\xf1\xf4\xc8\x0f\x9d\xc3C\xd2\xc9\xdd\xf9+\xa0\xb8>\x9dB&\xf7k\xb73\xc2\xcbW\xe9\x83\xcc\xb5\xc5\x9a%\xa7\x9eE\xae\xb5\xcc\xef\xda\xff\xf5M\\P\x83SL6\xa2\xd8%\xb23H\x9e\xf5Z\xecd\xe3\x07xb\xbbH\xb2|/\xf9\xf7n\\#\xda\xb5()\xc8q^\xbbqIk\xcbK|\xfe`\xd0\xf0\xaf$\xad(\xa4GU4\xda9\xcf\xc0\xed\x16?\xfb\x10\xf3v\xb1\xe2fs\xc0\xdbQUG^\x12\xb6\xa2(\x03\xe2Q\xa2U\xaf\xd2\xa2\x96\x952\xa4Y@&P6\x0ez\xbc\xebm=m5\xdeT\xd7\xb0\xf8\xfce\xda{\x9el\xbd\xe5\x8bq\x1fe\xd4\xd5(\x95\xefE\xba\x1c3\xfa\x7f\xa5\xf4\x8b\x9c?\x01\x00\x00\xff\xffPK\x07\x08\xa7\x82\x8aT\x84\x02\x00\x00\xf1\x06\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x939vP\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x06\x00	\x00os.xinUT\x05\x00\x01\xe7\x0fw^\xb2V\xc8/V\xc8\xcc+I-JKLNU(/J,(H-*\xe6\x02\x04\x00\x00\xff\xffPK\x07\x08\xb4v\x1d@\x1e\x00\x00\x00\x18\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00e\xbagP\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x07\x00	\x00src.xinUT\x05\x00\x01\xee+d^\x94S\xcbj\xc30\x10\xbc\xfb+\xa6'i)\xa5w\xf5\xd0\x1f\xe9Eu\xd6EM\x94\x18I\x18\xdc\x90\x7f/z\xd8V\xdc\xe0\xa4\x0b!\xc8;;;\x9a]\xbd\xc1\xb33\xfa`~t0\xa7\xe3\xeb\x8e\xaf\xce8\x98O\xa7\xdd\xd84RAzv/>8\x0c\xd4\x00\x90\xcf\x10\x1fB\xc4\x7f\xe9\x83S\x8a}\xab{.\xd9*\"\x8a\x88\x16\x8e\x81\xdb\x89\xc3t\x90\x03\xb7J\xb1\xed\xc3\xf8>\x17\x8b\xf8\x95D>d\xfa\xce\x86\x898eq\xbeL\x00$\x92\xa5m.\xf8>\x99ca\xb7\xba\xc70_\x95	\x02QR-*A\x16QV\xf77DY\xddo\x88\xda\x9d \x15l*&\xfa\xaf\xbe\xdcr\xcf\xa3\x87][\xb8\x8e(\xba\xc3\xfe\x1e\xac\x96\x99\xd5+\xe59<\xc1\xe2|\xb9\xd2\xf7H$\xd3\xe5l\xe2C\xedWQUg5_\x1c0`Oy\x18\xb7J\xfeN\xca\x07\xc7\xda\x96\xa9\x08\x99\x8f$\"\"O`n\xe2|\x99E\x03T\xb7\xaf\xf3\xe6\x18\xe0\x83\xdb@tN\xb7w \xf1Q\x14e\x1b\xa8h_Y\xff\x0dT\\\x85\xb2\x8f\xdb\x1d\xa3\x07\x8b\x1dD\x93?\xc5\xdf\xb2\xcb\x8b\xcdW\xae\x841\xbdT\x8a\xbf\xe67\x00\x00\xff\xffPK\x07\x08\xc1]~\x023\x01\x00\x00\x05\x04\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\xdd<VP\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x08\x00	\x00stat.xinUT\x05\x00\x01\x93\xdaP^\x94R\xcdn\xf3 \x10\xbc\xfb)\xf6\xbb\xc1\xc1_\x9a\x1e\xc9!oR\x89\xc0\xc6F\n`\xc1:J\xfa\xf4\x15?Nh\xe5\xb8*'\xb4;\xcc\xcc\xcer\x80H\x92L$\xa3\"\\\xcc)\xc8p\xef\xba\x03\xc8`h\xb4HF\x81E\xe9:&\x80\xa5\x0b\xdc\"\xef\x00\x80\xed\x80]Q	\x11g\x9bj\xc0\xceA\xaa\xa5f>1\x159\xe7\x89k@o\x91BK\x95K\x0d\xdbG}9\x05\xaf\x0b\xdd\x0e\xf6\xff\xdf\xd6\xf8\x8a\x13m\x9a\xd7\xe6\x0c\x0c\xaf\xe8\x8e?\xf1\xa9\x0b\xc0\xb4\x07&`\x94\x97s\xe3\xbbb\xe0}\x81A\x9d\xb0\xf6/F\xe1r\xf7\x81\x16\xb1\xf5\xc34\xaa,\xb0\x851\xaeb\xf8\xc3Y\xa6\x1f\x90~\x11Zs\x0d\xfb\x1a\xaf\xf3\xae\xd7H\x18\xacqy\x91`\xbd\xc6\x92\x93\xd7\xf8HI\xfb**@\xf9\xd9Q|\xa8\x14\xee\x80zV	\xbe>B\xe2\x9b\xe68\xc2\x0d\xa4R\xaf\xe7\xcc\xdb\xb0r\x12b\x94\xf1\x98\xb0p\xdbH\xa5b#\xd2\xbf\x82-9\x95jJ\xa6\x10\xf0?Q\xec_\xe2\x13\xf4\x19\x7f\xfe\x89\xd4;\x1c\xfa\x1c	L\xd2\x84\xe7K\xe6phv\x94\x9a\x0dui\x8c(us]Q}\xae\xb6?\xdd\xabWt\x14\x0c\xc6\xba\x88\xad\xd9\xca\xf9\xe6\x92\xe7\x0f\xf4\x15\x00\x00\xff\xffPK\x07\x08\x1c\x9ea|F\x01\x00\x00\xba\x03\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\xd4\x8cvP\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x07\x00	\x00std.xinUT\x05\x00\x01\xa0\xa2w^|U\xdb\x8e\xdb6\x10}\xf7W\x1c\x07H)5uc\xe5\xd6V{\xf1\x9f\x14\xa0\xa4\x91LD\"\x1d\x92v\xbdA?\xbe\x18\x92\xb2\xe8\x8d\xd2}X\xd0g\xce\x1c\xce\x8d\xa3\x078/u'm\x87Q5V\xda\x97\xcd\xe6\x01\x8d1#I\x0d9*\xe9\xc8m\x8a\x1a\xde\x9e	U\xc9\xc7^\x8e\x8e\xb0/\x99\xf9\x8f\xd4\x1e\xde\xa0!\xc8f$>:\xf9\x827'\xe9\xdc\x9b\xcd\x03\x8c\xc6\xd9\xd1H\xceA\xf5;b\xc7\x96%\x7fCo,\xe8*\xa7\xd3H,\xca\x0e\xacY\xd4\x90\xba\xc3/\xe1d,\xfe\x0d\x07m<\xb6\xe1Tpl\xb8\x96\x1b\x00\x85\xeaq\x85\xe0\xd0\x04D\x08K\x94!,\xffr\"H\xe7\xc8zetH\xa0P\xda\x1ff\xc7'\x14\x81r-\xa1\xb4/\xa3roe\xbb\xc6`<Q\x9c\xb7k\x0c\xe7m\"\\hU\xe2B\xb3\xc2$Ok\x84I\x9e\x96+HN?\xb9\x85\xe4\x14i\xbd\xb1S\xb2\xf0\xbf9\x03cW=\x19\x8f\x85Q\x1di\xaf\xfc\x0b\xfa\xb3n\xb96\xb143z-\xd9{\xf3\x10<\xa4\xf7\xd4a4\xc3\xa0\xf4\x00w4\xd6\x1f\xa5\xee\xd8c4\x03\x8c\xab\xeb\xd1\x0c\xf1\xe6\xd1\x0c=\x1c\x94\xa7\xc9\xc5\xeb\x99\xc2\xc9\xd4u?\xf9\x9b)F\xd1H\xa7ZL\xd2\x1f\x83\xf3\xf6	\x12M\x89b\xcbQ\xf31\xe5\xf3\x9c\x19\x1es\xc3cfx\xce\x0d\xee\x9b\xf5\x9cE\xf17\xae\xf8\xfdsB5\x0d\xd0%\x8a\x1d\xf6\xd0	;\x19w\x08\xe034\xf6\x0b1\x82\x8f\x19\xf8\x9d\xac9\x04\xd1'\\o\xa8\xd2m`\xbe\x83F\x95\xb0\x8e\xdat\xcf\x82\xf5\xb2\xf5\xc6\x1e\xd0\xa8\x01n\x92\xe3x\xeb\xce\xdb\x0c\xbb\xc9\xd2\x85t\x0cav\xd4\xf8\x90l\xa6\xeb\xa2i\xbb\xf0ncu\x0de\x98\x9fE\xaaJ,Md(}\xcfx\xcc\x19<\x1a\x9e\xac\xe4\x91\xc0\xc9\xaaIyu\x89/\xbf\xb0R\x0f\xc4{\xc2z\x90\xee\xe0<\x9d\xa2L\xa8\xf8\xb9\x81\x82l\xdb\x00aQWL\xbe\x81\x88\xcc\xe2\x1d\xd4\"\x90\xfd\xf1\xcb\xa9k\xd9u[\xd6\x82*3\x02\x8b\xc7\x9f1\x88\"<\xa7r\x13;N\xdf\xa0c81\xd0}*~l\xa8\xf4\xf7\xd6\xea\xd6\xb9\x1b\xc7\xcb\xaf\x84\xcbL\x0bq\xb8Q\xb5\x8c\xc5qI\x13nN\xd0\xe8_e\xfec\xd6:\xcf\xb93(\xfa\x8c\x85\xbbBTe\x9e\xe6>\xfd\xd8\xc7~8\xf2\x90\x8d\xf3V\x86\x87\ns!\xcb{\"e\xedc \xd9\xe2 \x9f\n\xe8p\xb9\x19\xeb\xda\x91\x0f\xd0<b\x81\xd7\xd1\xb8E0\xf3i\xc1\x8f\xd2\x1d\"\xce\xa7\x05w\xea;E\x9cO\x0b\x1e^u4|\xa5\x17\x17\x02?\xd2\x15\xa4[\xd3)=\xbc\xef(\x1e\xd8\xc3\x9b\xdd\x91\xae\xbbN\x0d\xca\xbb\xb9\xd8\x10{\x01Q	\x88\x0f\x02\xe2\xa3\x80\xf8$ >\x0b\x88/\x02\xe2\x0f\x01\xf1\xa7\x80\xf8K@H\x01\xd1\x08\x88V@t\x02\",}\x91\xb2?\xd2\xb5\xae)t6\x9bp\x8d\xeaK\xaapl\xec@\xfe>\x8c\xf0]\x08]\x8e\xacw\x99T\xf1>\n,=\xfa_\x91\xe2m\xa2\x97\xf3pz\xb3\xeb\xa8\xdd\xe9\xf3\xd4\x90\xddq\xef\xe6\x99\xa81\xcd\xbd\x8b\xc2K\xab\xa6\x90i\xb5_\xb54\x02U\xb5ji\x05\xaa\x0f\xab\x96N\xa0\xfa\xb8j!\x81\xea\xd3\xaa\xa5\x17\xa8>\xcf\xcb\xe5.\x0d\xb8\xa5\xc0a\xbd\x87\x12\x1c\x12\x8cX\nw'\x9a\xeau_\n\xb82o\x1d\xaf\xceL\xf9)\x89\x87\xb9s\xfcX\x93\xe0Z,\xb1m\xbffJ\xc99\xbcb\x87}\xdc\xccw\x8aw/\x0f\xcb\x98\xfcxGt\xe3\x1c\xdc\xbaN\xfa\xa8uV\x86\x15\xeb\xcc\xd9\xf2\xb5\xe1k\x1d\x12\xdc=?\xa3W#\xa1\xcd\xd6o\xfc\xaa0\x9cn\xde\xa7T\xe6\xd5\x92\xafT\xb0H\xd4\x08\xdf\x144\xe7\xfe.\xfe\xe5/\x13\xff9	(\xda\xd77\xbc\xb2\x87%\x152\xbd-e\xd6+\xb3\xca	\xc1\xe7\xff\x02\x00\x00\xff\xffPK\x07\x08z\x95\xd7\xaf\xd1\x03\x00\x00C\n\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\xaf\x8evP\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x07\x00	\x00str.xinUT\x05\x00\x01\x1a\xa6w^\xbcX\xd9v\xdb6\x10}\xd7W\xdc\xbc\x94d\x1d\x9dXN\xd34R\xec|B\x7f\xc0/\x10\x08JHH\x90\x07\x00\xb5\xc4\xf1\xbf\xf7\x0c\xc0\x05\\\xa4,n\x8b\x17S\x98\xed\xce`\x16\xc0\x1b\x18\xaba,S)\xd3)r\xb9\xd5L\x9f\x17\x8bx\x8dx\x9b3\xf5\xe5\x13L\xb2\x00\x10\xdf\xc3 \x8a\x92\xc4\xd3\xb48\x08mDK\x94\xd9\x88\x1d\x88\"\xff7\xbe\xe9\xb9cc\xf5zmr\xc9\x05\x0cV\xedo\xf9\x95\x14%\x8d \xc9\xb8\xfd\x9d\xb00\xb8M\x92\xdehZ;\xc9\x0c\x8cso9-\x1b3k\x14\xec4\xd2\xd8\x91bSo!;)\xbfM\xa0?B\x92\\\xbf\x0b\xcf\x1b\xdf@b\x15n{Z6\xc0&\x13R	\x99\x84\x8cd\xa4\xb5L\xaan\xfdN\xb2Xl\xb0\x15\x9c\xd5F\xe0(\xa2<\x07}\x15\xac\x023`\xc8j\xc5\xad,\x15\x14+\x04\xf6B\x8b\xd7\x8b\x0dX.\x99\x81\xdd\x0blkis\xa9\x9c@\xcb\xbbp^W\xeb\xb5\x12G\xfaH\\\x98\x88\xc3 \xf3\xf1ic\x16zBL;\xf0\x06\xfb\xc8G\xef\x1fK\xd3W\x8e\x1egp\x0e\x0e\xd8\xfa<\xc8dn\x85~\x899:\x05oc\x1c\xec1\x14>\xe5\x08b\xedW\x8f\xecs)\x15\x0e\xa8X\xda\xa7\xe8A\xf0\xf5Z\x14\x95=\x7f\xc2a\x9c\xa7\x8e\xd8\xe0\xf7?,\x93y\xc77X\xceu\x98aB\x0d9n|\xf8n\x08\xc10\xbb\x03&gf/X\x8aC\x9f\xe6R\xa5\xe2\x04\x03So=t\x9f\xfba\x88\x9c7\x0f\x90\xb3\xf9Nk\xb9\xea\xbf\x1d\xf3\xfd\xa8\xf8\xa4O\xf1P\xbe\xde&I\xd2Ym\x97\x1c\x1c%\xc1hj#i}\xbam\x81\xef\x99\xf9\x14\xe2~\x18\xfa\x82\xe5\xaa\xe5\xac\xb4\xc8\xe4i\xc0|\x0fWw!\xc8\xf9\xc8\xe2\xf6\xc2\xfe\xc4\x97\xc6\x98\xa9\xb3_7\x16/\x871\x9eX\xf9\x010IS\xfd\xb5\x95\xb9\xb4g\xd8\x12|/\xf8\x170\xc3\xa5\x84fj'\x8c?z\xb3\x94j\xe96\xa0P\xb8r\xf7\xcd)\xfe\x0d\xf1\xc3\xbd\xdfL\x10\x7ft\x9f\xec\xd49\x99\xca\x9d\xb4]\xb3\x0e\xf5x$BqB\xff\xc7_x\xf7\xbe\x95\xa9\xabJ\xe8\xef\xcb\xfc\xf9\x0e\x1f\xba3\xce\xcb\xe3\x8f\xc8|x\x8f\xd5\xdd]'$\xac\x0d\xa4\xbe\x05\xa6\x03\x8d=,\xce\xfa\xd1\xe2\xbaY\x17d_x\xc3\xa2s\xf9\xddh\x19\x97\xa3\x07\x95R\xd6.\x03\x84<\xc1\xdb\xbb\xf1\xd9\xf1>e\xd2\xf2\xa8~\x01D\xe3\xd5\x15\x107?\x01\xa2b\xe9\xd2X\xa6i\xd2p\xea\xfa2\xcf\xfb^\xf6q\x94\x97\x9c\xda\xbf7\x97\x96\x041\x95YF^\x93\xe8\x85A\xdb\xc1\xf15\x10\xff\xeelP\xd5r\xc4o\x1a\x0d\xbd\xa8\x03@=\xe2\xd6\x91B\xe4]\xf31!z\xa1\xd2\xff\x12{X\xb3/\xf1c\x80}\x83\xb2\xb2\xb2\x90_E\n'\xa2E\x953.p\x94vO\xd5'\x0b\x96\x83\x97\xd5Y\xaa\x1d\xde\xe0\xefX%0\x82i\xbe\x97j\xd7\xdcS\xbc\x84A\x99\xa7P\xe2\xd8\xb7qj;\xd4\xfc@Mi\xd8\xce\xef\xc9q\x99\x9e\xba\xa6Yg\x19)H\\\xe3\xec]\xa5\x912\xd1\xd0_[\x886\xedI\xa3\x93v\xbao\xc9\xda|\xff\"\xccc\xcaX~V\xb0a\xbd\xf1\x8e\xf4!w~\\\x93\x08\xbb\xaa;\x92\x86=\x8a\xfa\x94\xb2Z\x16]ETL\xdb>\xa3\x9a\xcbg\xb7\x8969\xba\xa8\xeb0V$\xd2\xce \x1dJ\xb5hF\xd3A_\x81>\x00?Qu\x8dY\x8fn\x05\x9a\xc6o2\xf0\xd6W\xd0\x8b}mG\xe0\xcb}\xbd4|\xfd\x1aNK\x9dLB\xf3]\x8f\x07\xdev!\x989\xfaF\x1f\x15\xacT\xee\x86l*\xc1%\xd5&5\xee\x92\xbc\xaeri\xfd\xb3e\xb1\xc1Q@*c\xe9\xb2\xe5	\xcc\x82\xde%gl\xcfV\x80\xef\x99f\xdc\n\xed\xef\x0c\xc4\xb1\x94\xca\x96K\"\x98v\x10\\\xba\xdff\xfe~;\xa9\x19w\xc1\x0bn\xb0\xc3\xd0\x1f\x84{\x18t\x06a\x90\x8a\\\x16\x93\xb3\xeewq\x11[p\xfe\xa6\xde\xbag]\xbd\xcd\xa5\xb1\xe3d\x986\x1a\xc7\xedm\x8c\x9a\xcd\xc0\x85F_#p=\x8b<\xcf\xf5d\x99t\x89\x06\xc2\x8f\xd6O\x83c\xca?\xc5|M\xe5O\xc2\x0e\xd6\xf5b\x08\x165\xda \xf7M{\xf4.}u\x9d\xcaB(\xcb\xf4\x99&\x0e\x8d\x95\xac\xd4\x05\xb3\x96>\xdd(\xd9\x97y*\xb4\xf13\xe8sm,\xa2\xa7\xe7h0\xab\x98JA\x0f\x96\xa5\x16\xbc\xd6F\x1e\xe6'\x16=*-\xfb\"\x0cX\xe6^n\xc1\x88[l\x16\x1b\x88S\x828+,\"_nO\xcfxz~\xfd\xf4\x0c\xa1R\xb2\xd8-r\x01+\xdc\xe1mB\xe5\xb5|@+\xb2\xc2\xdd\xeb\xb7\x9e\xdf\xd5F\xe1\xde\xcaV\x14\xe6\xc2 \x0c\x88h\xb3\xf4\xdb\x85\x89H\x8eO\xb3\xb4;\xf4\xe6u\xe7\x15\x86\x99\xff?LM\xe2\x0d\xdft\x0d\x88\x7fa\x92\xde]\xab\x89\xc9\xec\x9c\x0d\x8c{\xcc\x0e\x11\xd1l\xed\"\xb5h\xae^\xc2pw\xdfu\xffTp\x8c\xfe\x97\x11\xf6\x95#F\x8f*B\xf4\xf8\xa8\xa2Y\xa2vD=O\xb4\x8eh\xe7\x89\x8f\x8e\xf8\xf88O\x8d<\xd5=\xf0)\x81\x84\xe1\xac\x1a^\xd0g/\xe6M:9u\xee\x81J\xea\xa6\xafv\xcf\xb0\x13\xf6\x02\xddW\xeb?\x01\x00\x00\xff\xffPK\x07\x08+W\x1b\x9a%\x05\x00\x00/\x13\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\xc5\xb2bP\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x08\x00	\x00test.xinUT\x05\x00\x01\x13\x87]^\xa4U\xcbn\xdb0\x10\xbc\xeb+\xb6'S@\x95:W\xa5\xa9O\xfd\x0b_hj\xe9\x08\xa0E\x86\xa4\x82\xa4\x82\xfe\xbd\xe0\xc3zR\xb5\xe1\xf2\x90\x08\xda\xd9\x99\xd9Y\xda~\x01\x8b\xc6\x82n\x9b\x06u\x96\x91\x12\x88aR!\x08zB\x01\x8c\x1a4y\x06\x00\xa4\x92\xee\x1f\x00)\x81\nQ\x98\x961\xc4\n+ \x1f\xc8\xca\x12?P\x7f\xc5\xe7\x0bU\xa1\xd3\xff\x1d\xa1\x87<\xcf\x07\x12&\xdb\xc6\xaehL\xfd\x07\xe3#\xaf\x85E}\x8bH\xc83\x87]\xd7\xff\xe8\xfa\xa3\xed\xfa]x\x1d\x8e\xe3Y\xeaL\xeb\x03(\xea\x8e\xd3\xce\x8e\x8fb\x10\xac\xf9|\xfe+\\Qc\xb2\x19'R\xf6\x168W\x94>\x01\xc2\x81\xad\xd5B\xb5\xe6@\xfc\xcc\x9c\xd6\x02\xab\xc3&r\x88\xe0h\x0b\xe8\xfacs\xb4\x00\x8b\x1c\x12=.\x19R\xa1a\x85\xe4\x85\x13\xfa\x87\xc0\xb4\x8f\x1a\x83\xda\xd6\xb2\x99\xf4\xe5\x9b\x9d.\x93\xdc\x9fp\xb5|\x83S\xd5\xb5r$0\xd0\x85;\xe6\\\xa5\xcb\xb9\xefO\x18\x0eQ\x9f\xd1\x02\x83}\x84\xa5L.\xb1\xcf\x11\xbb\xb8XW\xe4k\x9ae\x90H\xec\x86|K\xb1\xb9\xd1_\xc6AL6q\x08\x1aM+l\xe8v\x1b\x7f\x8do\xc0\xea\x16c\xaa\xe3\xbd\"\xc6\xea\xb2\xe4\x17\x0b\xbb\xdf\x9f\n\x99\xc5\xca\x03\xe1\xd4Z8K\x9bZ\xbb\x8f4\xca\x0ck\x08\xea\x05\xa7\xc2\xe0\xb6\x07_\xbe\xcbD z\xd0\x05\xbe_\x051\xf2%\xbc\xccJ\xb7\xect\xfdM/W\xbe\xa5)\xaa\x94\x96\x9f\x05\xa3\n\xf6O{w\x9e\xf3\xb9\xd9\"@\xb6=\xff\x04r\xa1\xf6\xad,\xe9\xc9\x00)V\xc0|\"r\xd78\x01\xfe4\x1f\xeb;H\xce\xe1\xf4\x05\xbfn\x8d\x98\xfcl\x06S\xc9\xd2\xc4\xddzU\xc5\xb8\xc8\xc4\xe8\xe1[\xef\xfd\xf0\xd0\xd6\x1c\xf3\x7fln\xe2\xd1\xfd\xfclz\xbcP\xf5\xb8G\xc7\xfc\xa0\xc7\xbf\x01\x00\x00\xff\xffPK\x07\x08w\xe4\xa1\xb3\xdd\x01\x00\x00i\x07\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\xcf\x98\x83P\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x07\x00	\x00vec.xinUT\x05\x00\x017\x89\x87^\xa4X\xddv\x9b8\x10\xbe\xf7SLnz\xc0.\xc7\xce\xad\xdd4\xcf\"\x83\x88\xc7\x01\x89 \x01vw\xf7\xdd\xf7\x8c\x84\x90\x108\xd9n\xb9\x8aG\x9f\xe6\xffO9A\xcfsP\x9a\x89\x82\xb5\x05TxnY{\xdflN0p\xe8\x14\x07\xa5\x8b\xe3\xf1\xaf\x1a\x05\xd4\xec\xf6\x0f\xa0\x00}AE\xc0\xef\x9b\x13(Iw\xd5\x05\xf4\x85\xd7\xc0\x06v\x873\xbf\xa0(\x88@wA\xb0\x9a\xab\x86\xe5|\x93\x1c-3\xc3\x0bE\xea	\xecF\xcc\xd3\x0dQ\x92\x0bg\x05\xf4\xe9\x06\x00\x92\x9e\xe7\xc7\xe3\x1b\xd7\xd0\xc3!\x1d\xcf5\xc3jv\xae*\xcc9\xf4\xf0\xec~\xe3/\x0e}\xea\xf0\x15Sz\x85_\x92\xcd\xe1\xf0<\xdd\xe0u\xa3\xef\xaf\xee\xceK\x84\x9b\x14\xe1\x1f\xaf\xd0\xc3`QX.\x91\xc1\xaf!50\x80\xa4\xe5EG\xea\xda\x9f\xee#~%\xdc\x80\xe59`:?\xa3\xe3o\xe6$y\x81[`\xc4\x00\x98\xa6i\x84\xd6m\xc7GR\xc9*\xc5\x9d\xb6(\n~\x83\x1enV_\"\xb5<\x14fL\xf8	\x18y\xd1s\xcf\x9e\xfd\xdf3{\xadC1\x1dy\xbb\x0f\xc3\x1fFX\xb2\x034~\x1e\xd9N\xae\xbc0\xf5\xeau\xfb\x19j\x0b\xd9\xb3C\xb5\xbc\xe7\xad\xe2.0\xa4\xc2,T6\xc0\xc1\x9f\xc7#+\x8a'\x7f\xd1\xe5N:e\x99\x89\xf9	\xae\x92r\xb2\xd3Ls\x05\xbd\x11F\xa4'\x1f\xdf1l\xc3\xcc&\x1f\xb48d\x81t\n\xdcm\x1e\xa5\xde[d\x93\x01\xca\x89IR\xc8\x8d\xe3N\x85\xb1\x16\x0d\xba\xaa\xba3\xe0\\\xb4\xf1\xc8\x0f@[L\x812\x84\x1d}\x1f\xa7VR\xc6A\xb4)\x18\x02I\x88\x93L\xac\x0e\x96\xe2\xca\x80\xe5\x17\xb2\xe0\xf7\xb5\xff\x0f\x9a\x17rE\xc3\xa8@B\xf3fz\x1f\xe6ZO\x1a\xd7\xac\xf1\n\xaf\xd5#\x81\xde\x1eUc\x14Z\x93\x01\x18\x95\xa1\xc9C\x9bZ\xd4\x07K\xa64W\x1a\xa8=j	y%\x05\x07\x067\x14\xd4}7'@Etj\xb7\x04\x17Lc\xcf\xc177\xa3\xb5\xbd\xb5\xd6\xf8\x0e\xeb\x8d\xaf\xc4J\xf3\xf6O,\xa5\xa8X\xf3\xe2\xb4Y&\xf8\x02\x11d\x8d\xbf\xe2\xb3\xa6\xe7\xed\xddY\xf3H\xb3G\xc5\xf5m\xad\xa8L\xe3\x1b\xb9+Y\xf3\xff\xcb\xfc\xef5\xe6\xb3V\xfa\x0b\x1b\x90\x8d\xef\x0da\xce\xa3\xc8L\x18\x92i\xd4}6\x13|E\xf4\xd4\x9c\xde)\x9a\x8eEp8\x8c\x87\xc3\xda\xe1\xc3N\xf0B\xf5\xe4\xf0\x811,\xcf\xc3\x123\xd5\x83bm\xee\xc4\xa9.\x9b\xa8\x14\x17A_~\x8bq\x15\xb5\x921+L\xad|t\x98\xbf+\xd9j\xe8\x14\x8a7\xb8H\xd6rhX\xabQ\xa3\x14ch[\x9d\x9d\xef\xd0C\xd3\xf2\xc2\x05\xc0\xb80#\x8a+os\x1azi`\xcd\x13 \\\x03/\xb9\xc09\x90\xaec\x03\xd3\x05`\x14\xe2Q\x96\x10Am\x94\xb9\xa6	\x823\x9e\xd7\xc7\xc0+\xf1\x7ft:\x8aYJ~\xccp<\x9f\xb4\x0e\xf3-\x99\xbc\n=T\x12.8\xf3\x0bA\x1a\xec\xa5^\xca\xabd\xec\x95\xa4\x8a\xda\xb9=0\xfb\xc4\xcb\x9a\xab,\xebE\xf6\xe0\xc8\xc9\xaf	\x91\x9c\x96N\xafkr~\xac\xc8\xb9>\x92s\x1d9%\x19\\W\xe5\xd8\x8aZ\x08\x9a\xa5\x8b\x03\xe3d\xfe\xb2|\x8e\x93\xa8\xeb\xca\xe9\xe8\x9f\xa5 \xfa\xaeK\x92	\xcbJ\x1a\x87\x90\xc0}\x81ys\x03	c#\x1e\xe6\x83\xaf\xbde>\xaclZ\xe6\x0b{\xea8\xc2\xa3\xab\x10\xa4\xd3Z\xce\xc5V\xc4j4\x9f\x02\x92\x1d4d\xe9\x82Q\xefM\x0b\xf1\x87\xf5\xad\x7f\x1a\x1a-U\x8dm)\xbe\xcd`\xc1\x85F}O\xd3	\x15\x82\x9e\xfcd\xb6ML0\x1a\xde\x9d\xc0\x0f\x90%0\xa8Pi\xb3\x070\x0d\x85\xe4\n\x84\xd4\xc0\x94\xeaj\x0e\xc4\x80\x9d\xb1B}7\xcc\xcd\xad/\xe6\x16+\x8a\x0c\xcb\xcc@\xd7'\x18E\xc2l\xd4\x8f'\xf3\x82\xb6\\Xg\xf7\x82\x85\xe6\xdcaUPw>W2\x7f7\x8b\x0b\xab*9@g\xfe\xcee\xddHe\xb6\x17-[\x90\x8d\xb2\xdb\xcbH\x966\xa2D\xf2\xaf\xa7G\xe6\xc2\x97\x0f\xa3\xb5\x11\xf5\xe5\x13i6s\xe8\xf9+K0\xf9 `@}\x01^\xf1\x9a\x0b\xad\x88~3\xda\xcb\x12\x84{\x9dl\xcduz\x99\x08\xcb\x81\xe5yWw\x15#s\xcbN\xe4\x94\xe0j\\4o\x9f<V\x0ec\x86N\x8f\x80\xe91\x1c\xbeN\x0c\x1fZ\x14\x7f\x97\x0f\xad\x1e\x11\x1f\xd5\xd5qv\xc1\xce\xac\xc9f\"\xb4\xb2X\x1co\xe1\xd9Z9:e@\x13D\xde\x92\xb9\xc6\xca\xdd\xceGwgYe\x99'e\x96\xb4\xddz\xd2\xd6\x92\xf6{O\xda[!*g\x15k\xe7\xfc\x93~G6Y\xbdv\xf47\x85c^\xc5\xcaY\xd8g\x1e\x9be_`\xb7\x1e\xbb\xdd~\x81\xdd{\xec~\xff\x18\xbb9AA\xe5-\n\xc8[\xa9\x14\x90S\xbb\\\xab\xef \xf5\x85\xb7T\x1eg\x14\xde\xb8\xb2bz\xe1t\x9b\xf4\x83)n\xf7\x12\xa5\xec\x1e\xd2t\xaaD\xc2\x90\xa8i\x0b\xa5\xe0Z;\x06wn\x85\x11\xc7a*<\xda\x8e\xac\xd4\xc8\x84!\x9d=\xf7]\xe1\xcd\x8bnY_\xc9~\xfe/\x83!\x1d'\xce\xbf\x01\x00\x00\xff\xffPK\x07\x08\xcf\x94\xe7\"	\x05\x00\x00Z\x12\x00\x00PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x0de[P.\xc1\xc1\xd4\xb2\x00\x00\x00\xff\x01\x00\x00\x07\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x00\x00\x00\x00map.xinUT\x05\x00\x01\xbb\xb8W^PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x87\xb8bP\xa7\x82\x8aT\x84\x02\x00\x00\xf1\x06\x00\x00\x08\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\xf0\x00\x00\x00math.xinUT\x05\x00\x01\xee\x90]^PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x939vP\xb4v\x1d@\x1e\x00\x00\x00\x18\x00\x00\x00\x06\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\xb3\x03\x00\x00os.xinUT\x05\x00\x01\xe7\x0fw^PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00e\xbagP\xc1]~\x023\x01\x00\x00\x05\x04\x00\x00\x07\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x0e\x04\x00\x00src.xinUT\x05\x00\x01\xee+d^PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\xdd<VP\x1c\x9ea|F\x01\x00\x00\xba\x03\x00\x00\x08\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x7f\x05\x00\x00stat.xinUT\x05\x00\x01\x93\xdaP^PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\xd4\x8cvPz\x95\xd7\xaf\xd1\x03\x00\x00C\n\x00\x00\x07\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x04\x07\x00\x00std.xinUT\x05\x00\x01\xa0\xa2w^PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\xaf\x8evP+W\x1b\x9a%\x05\x00\x00/\x13\x00\x00\x07\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x13\x0b\x00\x00str.xinUT\x05\x00\x01\x1a\xa6w^PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\xc5\xb2bPw\xe4\xa1\xb3\xdd\x01\x00\x00i\x07\x00\x00\x08\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81v\x10\x00\x00test.xinUT\x05\x00\x01\x13\x87]^PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\xcf\x98\x83P\xcf\x94\xe7\"	\x05\x00\x00Z\x12\x00\x00\x07\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x92\x12\x00\x00vec.xinUT\x05\x00\x017\x89\x87^PK\x05\x06\x00\x00\x00\x00	\x00	\x000\x02\x00\x00\xd9\x17\x00\x00\x00\x00"
+	data := "PK\x03\x04\x14\x00\x08\x00\x08\x00G\\\xafP\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x07\x00	\x00map.xinUT\x05\x00\x016~\xbe^\xac\x8f\xc1j\xc4 \x10\x86\xefy\x8a\xffT\xf4\x10\xe8\xd9R\xf2,6\x99\x96\xa0\x13\xac\xb1\x82}\xfa\xc5\xa8!$\xbb\xb0\x87\x9d\xe3\xf0\xcf\xff}\xf3\x01\xd6\x0ek\xd0\xcb\xa4\xfd\x04;\x7fy\xedS\xd7	\x05A\xecB\x1a\xc0\xb2\x03 >!X;\xa5\xd6\xf9\x9f\xc0\x12\xefR\xd6\xd8\xef\x00\x86-\xa9\xb7k\xf0pf\xe5\x96\xcau\x91F\xa5(\x92Ou\xd3v\xd9\xa7\x9c\x18Jk\xa5\x9f&\xdb\x8d\xec\xfa\xa8-\xb87\x94\xee\x85\x8e\xd2?\x14\xc05\xba\xb5\xf7y\xd3\x8e\xcb\x94o\xa2\xb6\x7f\xd4\xb0O\x19e\x99o\x98\xabB\xe64\xb4\xd9?\xaf\x93\xabw(-\xc1\xcf\xaf\xa2F\x1aa\xce\xf0\x07\xf4\xdb\x00PK\x07\x08.\xc1\xc1\xd4\xb2\x00\x00\x00\xff\x01\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00G\\\xafP\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x08\x00	\x00math.xinUT\x05\x00\x016~\xbe^\x84TIo\xf36\x10\xbd\xebW\xbc\x1e\x8a\x92N\xdc,@/v\x96C[\xf4w\x8c(Jf\xc1E!\xa9\xa4\xe9\xaf\xff0\xd4bK\x89\xf1\xf1`\xc03o\xde\xbcY4G8\xca'\xed(\x1b\x95`M\x1d)~V\x958@P\x9d\xe0e\x05@\x98\x16\xe2	\x1e\xf7\x12\xc2\xeb\x0e^\xc2KY`\xbd)\x88\x1d\x1e!\x98\xeap\xa0d<\x1e$\xfb\x8f\xf8{P\xd64\xbf%\xfc\xf3\xe7_ \xdb\x85h\xf2\xc9q\xa0\xe8T\x03B]2\x1c\xd1G\x1d\xf5\xdb\x01\x84'\xd4l\x12M\x00\xc3\xd2P/\xb0\xe9\x15=\xcf \xdc_Z\x81z\xf5\xafD\x8a_Q\x83$\x88\xf5\xb0\x959i,\x8e.L\xf5h\xaa\x17\x13\xa7xY%.|L\xb620b\xaa\xd5jJ\x19*8\x17<\xdc`\xb3\xe9\xad\xc6\x90\x8c\xef\xd0\xa9\xa6\x14m\x95[H\xc5\x8e\x95\xdc\xa1>\xf7BN]\x15}4N\xbfn\xfb\xff8\xe5n\xc9&=	\x1d\xdb\xe4\xe8?\x08\xe3\x15\xffd\x88\xf4\x163\x8fh.\x07\x10s/\xcdbZ\xea|\x86a\x82\x95\x03\xc8q\xd0\xd5\xa5a\x04\xb7\xa4r\x88\xaf\xf0\x1b\xa6\x8d\xae\xf3+Y\x8b6\xc3\xf5I<\xce\x0d+Eb$4\xffkx\xcaC$\x0b?\xb8Z\xc7s\x1f\xf6#dY\xc7\xb9\x94\xbeMp\xe8[Y\xad\x17\xc3\xe1a1\x81Q\xd5\xf7%\xacb\x97\x89\x8aw\xad\x0e\x07j\x9a_8t\x0b)O\xdc}\x8d-o\x03\x9eU^\xe6(\xcf}\xb1\x94\x06\xf5\xad\\f\xc62$<w\x8b\x97\xebj\x97\x8ehC\x84\xa5\xd8i\xf8[8\xfaD\xad\xe1B\xd4\xd0mk\x94\xd1>#\x07\xb4&\xa6<7}\xea(\xd3\x91o\xd0\xc7\xd0\x0cJ\xf3\xee\xd6\xc6S6\xc1'\x84v5\xa0T\xe6qe\x126|\xe8\x88\xa1\xefuD\\ZP\x9a\xfd\xf2\x0c\xb1\x03\x9b\xa7\xf1\xcd\xf5\x96Q}\xe7\x02\xc4\xcd\xe5\x14F\xf23\xed\xf4FD\xd4\xef:&=\xe6>\xaf;?q3\x85~\x8b\x94\xd5\xb5\xbd^g\xda\xee\xc4\x155\xb3\x9e\xb27E\x0co\x89\xc7F\xd3T\xbbW[GIS\xb8\xbf\x10\x17\xb6\xab$\xeb}\xe1R%_\xdfr\x9c\x85\x0d\x1djJz\xfer\xee\xe6#m=\x1f\xf1\xf3\x1f\x06M_%YC\xa9,\x95hm\x08\x91\x81\xfb=\xfe\x1dR\xe6-\xe5\xbbr[\x1d\xf1q2\xea\xc4G\xc2+\xca:!\x9f4:\xf3\xae=\x1a\xad\x8c#\xcb\x02F\x02\xe3\xf3\xa4'\x86\xc17\xb3\x98r\xa9n\xe0\xf1\xfb\x1f\xcb\xdd\x8b\xe4\x9b=;\xe6{4\xa2v\xb3T\xf6\xcb\xe2\x9c#\x86\x9f\x85\x0c\xab\x98\x1f\x03\x00PK\x07\x08\xa7\x82\x8aT\x94\x02\x00\x00\xf1\x06\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00G\\\xafP\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x06\x00	\x00os.xinUT\x05\x00\x016~\xbe^\x00\x18\x00\xe7\xff; os interface wrappers\n\x03\x00PK\x07\x08\xb4v\x1d@\x1f\x00\x00\x00\x18\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\xecYR]\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x07\x00	\x00src.xinUT\x05\x00\x01L\xaa\xd4j\x94S\xcbn\xe30\x0c\xbc\xfb+fO\x12\xb1X\xec]{\xd8\x1f\xe9Eu\xe8BM\x94\x18\x92 \xc0\x0d\xf2\xef\x05e\xf9\x916qR^\x12[\xc3\xe1h\x86\xfe\x87\xc8\xc1\xd9\x83\xfb\xb0\xc9\x9d\x8e\x7fw|\xf5\x8c\x83{\x0d6\x0cM\xa3\x0dt\xe4\xf0'\xa6\x80L\x0d\x00\xfd\x1b\xeaE)\xf9\xd51\x05c8\xb6\xb6\xe7z\xba*A\x11\xd1\xc2\x91\xb9\x9d8\\\x07\x9d\xb95\x86}\x9f\x86\xffs\xb3\x92\xb7\xa4\xc6\x87\x91\xbe\xf3\xa9\xa9\x9c\xe5\x14\xe7\xcb\x04@!\x99\xfeO\x0d\xef'w\xac\xec\xde\xf6\xc8\xf3U\x99\xa0 \x92\xd6\xa2\nd\x11\xe5m\x7fC\x94\xb7\xfd\x86\xa8\xdd	\xda\xc0\xa3\xc0\xe8\xa7\xfa\xa4\xcb\x98=\x0fq\x1ex\xb7$\x8d\x0e\xfbG\xb0\xb5L\xa8q@\xe4\xf4\x0b\x1e\xe7\xcb\x95\xbegJ\xac,^\x95\xfd\xe0\xa7\xc6\x7f\xa9U\xf7\xa8\xe6\x8d\x132\xf6%\x8b\xdbt\xdf\x93\x8a)\xb0\xf5\xd5$%\x17d\xebII\x96c\x02\xf3\x90\x10k\x16\x0d\xea\xbcr\xfb\xf5\xb9;&\xc4\x146\x10]\xb0\xed\x03\x88|\x14U\xd9\x06\x91\xd8'(\xd9\xec\xfb(\xd9\xc3\xba\x8f\x1b\xa8\xea\xc1b\x07\xd1\xf4y\xd5t\xea./6\xaf\x87\xe84\xf4\x8cL\x84L\xd4|\x0e\x00PK\x07\x08X\xa3\x8a\xcf:\x01\x00\x00\x05\x04\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00G\\\xafP\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x08\x00	\x00stat.xinUT\x05\x00\x016~\xbe^\x8cQ\xcbn\xc3 \x10\xbc\xf3\x15\xdb\x1b\x1c\xdc6=\x92C\xfe\xa4\x12\x81\x8d\x8d\x14\xc0\x02\x1c%\xfd\xfaj\x0d~\xd4u\xd4\xee	\xed\xce\xcc\x0e\xb3GHYe\x9b\xb2\xd5	\xae\xf6\x1cU|0v\x04\x15m\xee\x1cf\xab\xc1\xa1\xf2\x8cK\xe0\xf4\x80{\x12\x0c\x00\xf8\x1b\xf0\x1bj)\xd3\xe0\xa8\x07\xfc\x12\x95\x9ez\xf6\x0b\xa9)\x84 \xad\x16\x83\xc3\x1c\xd7Rck\xa5\xf6Y\x99}\x0c\x86\x98\xa4\x7fx}\xdf\xd3+N\x8c]\xb1\xed\x058\xde\xd0\x9f\xb6x\x9a\x02p\x13\x80K\xe8\xd4\xf5\xb2\xf2]1\xf01\xc1\xa0\xfe\xb0j\\\xad\xc6\xe9\x1db\x9e\x96\xed\x177\xa8\xc7\x05\x82m&Kq\xeb+FL+\x8b|\x8b\xf9\x8fE{\xae\xe1P\xe3\xf5\xc17\x063Fg\xfdxHp\xc1`\xc9)\x18\x9c\xf4\xb8	u\xa9\x04\x1d\x06\x9f\xd3l\xb5hG4\x83&\xf8\xfe\x17H\xaf\x1fR\x07wPZ\xff\xb28\xd7x\x0d\xa7z);\x95N\x84\x85\xfbs4\x85N\xd8\x84\xf9\xa5`KNE\x81\x92\x19\x9bsb\xff\x938<\xc5\x13t\x1e\xd2\x97Z\xcc\x8d\xc7\xb6\x19#\x81^\xd9\xb80\xb9\xc7\xb6\x86CNh\xb8\x92.\x83\x0e\x95Y=g\xeeR\xcbi\x9b\xf3\xa3zE\x9f\xa3\xc5T\x0f!\xd8\x96\xb3\xad\x1f.\x85\x10B\xb0\xef\x01\x00PK\x07\x08\x1c\x9ea|N\x01\x00\x00\xba\x03\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x96LR]\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x07\x00	\x00std.xinUT\x05\x00\x01-\x93\xd4j\x84V\xedr\xdb6\x10\xfc\xaf\xa7Xe&\x05Y[\x8d\xe8\xc4iK\x7f\xbdIg@\xf2HaB\x02\n\x00)t&\x0f\xdf9\x00\xa4(\x97M\xf4\xc7\xe0\xeebq\x87;\x00~\x80\xf3R7\xd26\xe8Ue\xa5}\xddl\x1eP\x19\xd3\x93\xd4\x90\xbd\x92\x8e\xdc&+\xe1\xed\x89P\xe4<le\xef\x08\xfb\x9c\x95\xdf\xa4\xf6\xf0\x06\x15AV=\xf1\xd0\xc9W\xbc;J\xe7\xdem\x1e`4N\x8ezr\x0e\xaa\xdd\x11O\xac\xd9\xf2\x16\xad\xb1\xa0Q\x0e\xc7\x9e\xd8\x94'\xb0gVB\xea\x06\xbf\x85\x95\x8c\xc5\x8f0\xd0\xc6c\x1bF\x19\xc7\x861\xdf\x00\xc8T\x8b\x11\x82C\x13\x10!,\x91\x87\xb0\xfc\xeb\x91 \x9d#\xeb\x95\xd1!\x81Li\xff2M|B\x16$c\x0e\xa5}\x1e\x9d[+\xeb5\x05\xe3I\xe2\xbc]S8o\x93\xe0L\xab\x16g\x9a\x1c\x06y\\\x13\x0c\xf2\x98\x04\xce[\x92\xc3\x9a&2Q\xd6\x1a;${\xa6\xd3\\F\xd7f2\x9e$d\xadY\xcd!\x10IT\x1f\xa4^\xd30\x9e$\xed\xc9\x9f,\xad\x89\"\x13\xeb\xa0\x1a\xd2^\xf9W\xb4']s)\xc2\x1e\xcc\xe8\x98\xf3\xfc\xcdC\x08PzO\x0dz\xd3uJwp\x07c\xfdA\xea\x86g\xf4\xa6\x83qe\xd9\x9b.\xae\xde\x9b\xae\x85\x83\xf24\xb8\x18\x00Kx\xef\xca\xb2\x1d\xfcL\xc5(*\xe9T\x8dA\xfaC\x98\xbc}\x82D\x95#\xdbr#\xf00\xe5\xf4\xbc \x1e\x97\xc4\xe3\x82x^\x12\xee\xab\xf5\x9cE\xf6\x0fF\xfcq\x9fPM\x1dt\x8el\x87=\xa6\xfd:\x1a\xf7\x12\xc0gh\xec\x13\xa8\xa9\x8b\xe0\xe3\x02\xfcN\xd6\xf0\xberp\xe3\x8c*]\x07\xe5\x0d4\x8a\x845T\xa7u.X+k\xcf\x05\xaeT\x077\xc8\xbe\x9f\x1b\xfe\xfd\x02\x9bm\xe9L:\x860M\xd4\xb8K\x9ci\x9aHm/\xbaD\x0dr\x0c\xdb0\x9d\xc2\xb4+\x01K\n\xa5\xaf\x15\x8fK\x05\xb7\x86'+\xb9%p\xb4jP^\x9d\xe3E\x93Y\xa9;\xe2k\xc9z\x90n\xe0<\x1d\xe3B\xcc\xbaS\x05\x05Y\xd7\x01\xc2\xb4\xfe#\x14\x8bg\x10Q\x99\xdd@]\x0c\x16?>\xa8e)\x9bf\xcb^P\xf9B\xc0\xe6\xf13\x06\xc1ZN<\xe4\xe5\xe8+t`S\xa0\xfb\xb4\xf9\x81\xd5\xd2_\xb3\xc5\\\xb9Y\xe3\xe5\x17\xc2y\x92\x858\\\xafj\xc6b\xbb\xa4\x0e7Gh\xb4o2\x9f\xc3\x9c\xb3\xd63\x04d\x8dA\xd6.T\xd7\x1bQ\xe4)\xaf\xf0\xdb\xa7\x0f\xfe\xbby\x80#\x0fY9oe8\xa80g\xb2|-\xa5\xac}\x10g\x8b{\x8a|\xda@\x87\xf3L\x96\xa5#\x1f\xa0\xa9\xc5\x82\xae\xa1~\xcbfqt\xc1\x0f\xd2\xbdD\x9cG\x17\xdc\xa9\xef\x14q\x1e]\xf0p\xaa#\xf1\x85^]8\xdd\x07\x1aA\xba6\x8d\xd2\xdd\x87\x86\xe2\x80gx\xb3;\xd0\xb8kT\xa7\xbc\xdb\xa4\xa2C\xec\x05D! \xee\x04\xc4G\x01\xf1I@\xdc\x0b\x88\xcf\x02\xe2O\x01\xf1\x97\x80\xf8[@H\x01Q	\x88Z@4\x02\"\xbc1\"\xb5\xf7\x81\xc6\xb2\xa4P\xd9E\x87k\x14\x9f\xd3\x0e\xf3Ze\xd9\x91\xbf\x0e\x83\xdb\x81[dR\xdd,\xac\xb2\x0f\xd1 q\xbf2\xc9\xde'y>5\xa77\xbb\x86\xea\x9d>\x0d\x15\xd9\x1d\xd7n\xea\x89\x12\xc3T;\xf6]\x96j\x08\x99\x16\xfbU\xa6\x12(\x8aU\xa6\x16(\xeeV\x99F\xa0\xf8\xb8\xca\x90@\xf1i\x95i\x05\x8a\xfb\xe9r\xb9J\x03\xee\xb2\xc1\xe1z\x0f\xe5|I0\x13\xda_>Bg\xa4M\xbf\xde\n\xb8\xc9>\x94\x8e\xaf\xce\x85\xf3S2\xe7n\x83\xcb1\xe7\xbc\x16K,\xdb\xef\xa9r\xec\x94&\x87S\xec\xb0G\xb6\x00\xa3\xe3\xd5\xc9\xc3\xa5M\xfe\xbbF\xf4\xe2\x1c\xdc\xbaOz\xd4\x1a+\xc3\x15\xeb\xcc\xc9\xd6\x94\xfe9\xb8E-\xfb\x9e\xdf\xcf\xba\xc27\xe5\x0fP\xde\xa16\xda\x93\xf6\xee\x96\xff!\xb3\x11\x97:\xbe\xf88\xcb\xfeDP-,I>Bh\xad\x19\xa0<Z\xa9\xfax#\xef\x9e\x9f\xd1\xaa\x9eP/\xee\xf3\x1f\xd3K\xc5L>\xffc\x11\xbeR\xae<N\xfb8]\xdd\xcb\x8b\x1b\xc8v\xc9\x98\xe9\x16\xd5\xa9M3\xdf\xfe\xc2\x8a\xf1a\xfc\x7f\x11\x90\xd5oW\xb8\xfe\x05\x9b\x14\xe8\xcf|\xa2\xd3/\x14\xe1}	\xc5\x9a\x9f\x11\x9e\x91\xcec\x14	\x91\xe7\xf9\xe6\xdf\x01\x00PK\x07\x08\x1d\xb5\xc6-:\x04\x00\x00e\x0b\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00G\\\xafP\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x07\x00	\x00str.xinUT\x05\x00\x016~\xbe^\xbcX\xddv\x9b:\x16\xbe\xe7)\xbe\xde\x0chZ\xaf\xda\xe9t:\xb5\x9b\xf4\x11\xe6\x05r#\x0b\x11\xab\x05\xc1\x92D\x1c7\xcd\xbb\x9f\xb5\x85\x00\x01\x8e\x9b6\xeb\x1c\xdd\x18\xb4\xff\xbe\xbd\xd9?\x92w\xb0\xce\xc0:\xaesnr\x94jo\xb89%I\xb6E\xb6/\xb9\xfe\xfe\x15\x96%\x00\xb2kX\xa4)c\x1d\xcd\xc8{i\xac\xec\x89\xaa\x98\xb1\x03iJ\x14 {;rg\xd6\x99\xed\xd6\x96JHXl\xfaw\xf5\x83\x141o\xc7\xafn\xffN:X\xac\x19\x1b\x8d\xe6\xad\x97,\xc0\x85\xf0\xecY^\x073[T\xfca\xa6q e\xb6\xddC\x0dR~y\xd0_\xa0Hn4M\xc6\xdb=\xa1V\xd8\xc4\xdb~e\xc5\x04\x9bb\xa4\x12*\x82\x0e\xda\xe9\xdf\xbd\xd9u\xb7\xc3\x92d\x87\xbd\x14\xbc\xb5\x12G\x99\x96%\xe8\xa9\xe2\x0d\xb8\x05G\xd1j\xe1T\xad\xa1y%q\x90F\xbeKv\xe0\xa5\xe2\x16\xee \xb1o\x95+\x95\xf6\x02=/E\xa5\xe2\xcdv\xab\xe5\x91\x1e\x18md\xc4aQx\x0cC\xccbO\x88\xe9\x0e\"`\x8f)C\xecy\x9e\xbf\xf1\xf4\xac\x001M<\x8c\xf2\xa0P\xa5\x93\xe65\xe6T\xd1\xdb\x98Q\x16P\xc4\x92#\x8a\xf5\x1c\xd9\xb7Zi\xdc\xa3\xe1\xf9\x98\xa2\xf7Rl\xb7\xb2j\xdc\xe9+\xee\xe7y\xea\x89!\\\xdd\x8b\xe3\xaa\x1c\xf8&\x8b\x0c\x14\xb0\xd3\x84\x9a\xac\xec-\x11\xe9\xa7\xe1\xf94\xbb\xc7\xd5\x999H\x9e\xe3~Ls\xa5s\xf9\x00\x0b\xdb\xee=\xc6\x8c6\x8d\x8cC\xe4\xa3v\x035\xab\xa0\xd1\xc4j3>{\xe6\xebY\xf1)\x826\x95o\xf7\x8c\xb1\xc1j\xbfT\xfc\xe2a\x84\xda`\xbdO\xeb\x1e\xf8\x81\xdb\xaf1\xee\x9b\xa9/Xmz\xce\xc6\xc8B=L\x98\xaf\xe9\xb1\xc7\xd3u\x88\xd8r\xb4\xd6\xcf\xec\x07\xd9\xd1\x97`\xcc\xb6\xc5\x9f\x1b\xcbV\x13\xbdla\xe5\x05`|\xa4\x92\x1dZ\xa7J\xe5Np5\xc4A\x8a\xef\xe0V(\x05\xc3\xf5\x9d\xb4>.\xca\xae\x94^\xf9\x0dhT\xbe\xdc\xbb\xe6\x94\xfd\x0b\xd9\xcdu\xb7\xc9\x90}\xf1\x8f\xfcap2Ww\xca\x0d\xcd:\xd6\xd3\x01\x96Z\xc02\xfc\xe7\x7f\xf8\xf8\xa9\x97i\x9bF\x9a_\xcb\xfc\xf7#>\x0f\xdf\xb8\xac\x8f/\x91\xf9\xfc	\x9b\xab\xabAH:\x17I\xfd\x8cLG\x1a{\xee\xb6\x11|\x1c-\xbe\x9b\x0dA\xa6 \x15\xb3N\xe0\xf3;h\x99R\xfa\x16\x92K1~H\xf2J0|\xb8\n\xe9;,1\x96`^\x1f\xf5\x1f\x80\x08^]\x00\xf1\xf67@4<_Y\xc7\x0dM\x1a\xc1\x1b\x14\xaa,\xc7^\xf6e\x9ab\xc4\x11TeyMq\xcaUQ\x90\xd7$:a\x1d\xfd\x1e\xe0t\x05\x97\xfd\xdb\xdb\xa0\xaa\x15\xc8\xde\x07\x0d\xa3(\x11	\x1e\xd6\x9e4\xa8\x01\xc6\xe6cc\xf4R\xe7\x7f'\xf6\xb8A\xbc\xc6\x8f	\xf6\x1d\xea\xc6\xa9J\xfd\x909\x9d\x8dh$4%\x17\x12G\xe5\x0eT\x92\xaa\xe2%D\xdd\x9c\x94\xbe\xc3{\xfc?\xd3\x0cVr#\x0eJ\xdf\x85\xc3Q'aQ\x979\xb4<\x8em\x9c\xda\x0e5?PS\nfCP\xae\xe9\xa3\xa9\xfcah\x9amQ\x90\x02\xe6\x1bg\xcf\xea\x1d_j\x18\x8f-D\x1b\x99\xfb5\xfb\xd2^\xf7\x9a\xac\xb1%/\xe01\xcf)q\x84I~F\x8e\x17M\x88|r\x18\xf3~\\\x92\x88\x12\x94\xfc\x1a\x86K\x9a\x8e)\xe5\x8c\xaa\x86\x8ah\xb8q\x8b\xc3\xe7\xb0\x89>9\x86\xa8\x9b\xc8\xbc\xef\x18\xfd\x0c2\xb1T\xa0\xcfG\x91\x19e/\x83_\xa8\xba\xe4\xa9\xe9\x9d\x0c\xcb\xd0\xf8\x1dz\x90\xf7\xb6\xab\xa0W\xfb\xda\x8f\xc0\xd7\xfb\xba\xbe\xec\xdcdZ\x9a\xc9\xb4\xf4x\x7f\xe9\xf1\xc4\xdb!\x04g>}\xd0GsUi\x7fB\xb6\x8d\x14\x8aj\x93\x1awM'\xf5\xa6T\xd4<\xd3\x94%;\x1c%\x94\xb6\x8e\x0e[\x1d\x81;\xd0\xbd\xe4\x84\xfd\xc9I\x88\x037\\8i<\x14\xcf\xb1R\xda\xd5+\"\xd8~\x1a=w\x9c\xa6S\xf2\x99\xc3`w\xc0\x8bN\xb0\x13\xef\x89J\x01\x19\x0c\xc2\"\x97\xa5\xaa\x16y=\xee\xe2YlQ\xae\xdbv\xef\xafu\xed\xbeT6\xfe\xdc\xcf4\x1a\xcf\xdd\xd9\x985\x9b\x89\x0bA_\x10\xb8\x9cE\x1dO\xcc\xf2\x82.\x11 \\\x96\x8a;\x85\xc7\xb1\xe4_b^\xb0,5\xbe\x14\xf6\x8b\x8b!Z\xd4h\xa3\xdc\xb7\xfd\xa7\xf7\xe9k\xda\\UR;nN4qh\xac\x14\xb5\xa9\xb8s\xf4\xe8G\xc9\xa1.sil7\x83\xbe\xb5\xd6!}|J'\xb3\x8a\xeb\x1ctaY\x19)Zc\xd5\xfd\xf9\x89E\x97J\xc7\xbfK\x0b^\xf8\x9b[4\xe2\x92]\xb2\x83|`\xc8\x8a\xca!\xed\xca\xed\xf1	\x8fO\xef\x1e\x9f uN\x16\x87E.`\x83+|`T^\xab\x1b\xf4\"\x1b\\\xbd\xfb\xd0\xf1SF\x922\x0b\xe5d\x15\x8a\xa8o\xc9\xc3\x18\x8b\x88C\x96\xfe|f\"\x92\xe3\xcb,\x05\xa6\xb7\xbbN\xe1?<5)5C\xee\xf9;]\x00\x11\xc3\x08F\x7fw\x92^\xb1\xe4\x19\x86Q]W\x114;g\xbc\xd1ev\x8a\x88fk\xbf\x95$\xe1\xe8%\xad\xf0\x7f!\xf8?\x15<\xf4\xee\xcdJ\xf7\xc6\x13\xd3[\x9d\"\xbd\xbd\xd5\xe9Y\xa2\xf1Ds\x9e\xe8<\xd1\x9d'\xdez\xe2\xed\xedyj\xdaQ\xfd\x05\x9f\x12HZ\xc1\x9b\xe9-\xe1\xec\xed \xa4\x93W\xe7/\xa8\x04s\xd1\xa8\x03\x03\xfd\xe5t\x9e.\x18c,\xf9k\x00PK\x07\x08+W\x1b\x9aE\x05\x00\x00/\x13\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x08SR]\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x08\x00	\x00test.xinUT\x05\x00\x01Q\x9e\xd4j\x9c\x95\xc1\x92\xe2 \x10\x86\xef>E\xefIR\xb5\x99u\xae\xb8\xb3\x9e\xf6-\xbc \xe98\xa9\x8d\x01\x81L9k\xf9\xee[\x0dI\xc4\x84\x8c\xae\x1c\xd4\x82\xe6\xef\xaf\xfbo\xca58\xb4\x0eL\xdb4h\x16\x0b\xc6\x81Y\xa94B-vX\x83\x14\x16m\xb6\x00\x00V(\xfa\x02X\x83A\xad\x8c\x03\x14\xf2\xdd\x07\x80S\xb1\x8a\x85\xba\xfa\x83p\xaa\x1a\xbf\x1bn\xb1\x0f\x94\x9c\x0fWl\xd8\x8d\x16e.Af\x93\x03\xbaL:\x9cwy\x03\x19+\xd0\xca\\\x95\xb9'\x90\x190a-\x1aW\xa9&\xda\xcd\xb2N\x90q\x10u\x9d\xdbVJ\xc4\x02\x8b\x1e\xe8\x03\xcdg\xf7\xfb \xb4/\xc7\xfa\xcfk\xe8&\x16\x91\xaam\xdcD\xc6V\x7f\xb1S)\xab\xda\xa1\xb9'T\xab}	\xcb\xf3\xe5\xc7\xf9\xb2u\xe7\xcb2\xae\x9at\xc6y&]\x89\xf2^=\xbaY\xbeMC\xc2\xaa\xbc\xad\xbf\x17\xd4\xc2\x0e^\xdc\xf5\xe8k\x97B\x16F8y)\xaa\x1a\x8b\xcd\x8c\x9fQ\x0b\xb6.\x87\xf3e\xdbl\x1d\xc0\xa8\x0f\xd3E\x80\x13\xe3{\xfa/\xd6\xcch\xcc\xdd\xa4\x9e\xd0\xe4dYx\x10T\x11\xd0\xb8\x99J\x93\x08\x0cr^\xc2S\xa5\x8f3\x7f?\x01Lw8\xdf\xa3\x03	\xab.,\x059\x8e}\xedbG\x13\xdaG\xbe\xa5U\x86\x14	o\xd8\xb7\x94\x1a\x95\xbe\xbe\x16b\x17\x11!\x18\xb4m\xed\x02\x1b9\xfe\xd6\xed\x803-f\x8b\xd1\\1\xeb\x0c\xe7\xe5\xc1\xc1\xf2\xf7I\xa3tX\xf8@\xd8\xb5\x0e\xf6\xca\xa5l\xa7\xf6\xf4i\x06\x1b\x02M^\x8a\xda\xe2<\x83?~\x08\xc2G>K\x81\xc7\xee\x04\xb0\xd3K\xf4\xe3\xe6\xe8\x1e\xce\xf9r\x97\xa5\xd7\x1b\xb7Fhm\xd4)\x97B\xc3\xeaeE\xeb5\x1e\xa9\x1c\x8fy\x08I\x82\xf9\xfe\xfd\x04v\x10\xee\x9ds\xb1\xb3\xc0\xf2I`\x16%y\xa8\x9c\x10\xfer[\xd6wPe	\xbbO\xf85gz\x9f0\xf96\x03T\xf2(\xa2\xeb\xbb\x12U\x7f52a\x97\x7f\x8ax\xdcLj~\xa4LR\xbe)\xf1\xff\x9c\x8b\x18\xe9\xef'E\xe0\xed9\x08\xfd<#)?\xc9\xf8o\x00PK\x07\x08)\xfd\x9a\xd5\x13\x02\x00\x00\x1f\x08\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00G\\\xafP\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x07\x00	\x00vec.xinUT\x05\x00\x016~\xbe^\xa4W\xc9\xd2\xa36\x10\xbe\xf3\x14\xfd_R`\x0fe\xffW{\x96g\x91A\x8c\xe5\x01\x89AB\xe0I\xf2\xee\xa9\x16Z\x01\xe7\xcfT8\xd9\xdd\x9fz_\xa4+hZ\x81T\x84\xd7d\xa8\xa1e\xb7\x81\x0c\xcf,\xbb\xc2Da\x94\x14\xa4\xaa/\x97?;\xc6\xa1#\xf3\xdf\xc08\xa8;\x93\x08\xfc\x94]A\n<+\xef\xa0\xee\xb4\x032\x91'\xdc\xe8\x9d\xf1\x1a	x\x168\xe9\xa8\xecIE\xb3\xfc\x82\x84\xcb\xc5\xc8b\xbc\x08\x042\xa3\xf0\"CJ~\xa7\xa4\x06]d\x00\x90kZ].\xdf\xa9\x02\x0d\xe7\xc2\xf2\x15am\xc2\x97-\xab(hx\xb7x\xc9~Q\xd0\x85\xc3\xb7D\xaa\x1dyy\x99\xc2\xe1\xdd\x9f\xa0]\xaf\x9e\xdf\xdc\x99/+\x9c7\x84\xfe\xfc\x06\x1a\xa6\xc5R\xd6l\x91\xd1\xb9\xa900\x80|\xa0\xf5\x88\xe6.\x7f\xdd\x87\x8e50\x03\xa9*`E\x16\xb3\xf0\xcb\xff0\x9c\xfc\x0b\xccV*\x06e\x02V\x14N\xb0\xfb\xd40RKjH+\xa9\xb3\x96\xf1\x9a\xce\xa0a6\xcc\x1c\x15\x0e4Vf\\\xf8\n,6[\xc7\xd2\xcb\xf7\xf0;\xf1\x17m\xd1\xc0\n+\xdb},\xc0\x8d\xdf\x15\xe4G`&\xceV\xac\x0f\xe5\x9d\xc8o\xc1\xb6\xaf\xb1\xb5P\xbe;\xd4@5\x1d$u\x89a\xcd*UK\xc1X\x1b\x16/H]\xbf\x85\x83\xaev\n_ehIv\x85\x87\xc0\x9a\x1c\x15QT\x826\xca\x90\xf4\x16\xf2k\xd36e\xfbIK\x1c\xf7\x95k\xb4c\xe2\xe68\x8e\x00:x\xb4\x14\x034\x083\x98\xbc\x16\xd6\x81\x0b6\xc5\xaa\xa6=+\x97\xe3\x0d\x98?\x15\x92\xf2\x19\x18\x9e\x8b\xf5\x19\xac\x8d}D6_\xdeX\x0d>\x89h/K\xecE%\xf6\xbf\x11u^(\xd6\x07J\xaa;hh~\xdf\xfa\xff`y-v,\x8c\x0e\xae\xddK\xec>\xbb\x7f\x8b\xd5\xbe\xbf;\xd2\x07\x83]\nb\x91\xe8\xd6\xf7W\xdd\x18\x15\x16\xf2M\xdb\xb2T\xb1\xc1\xd8\xd2\xc29\xd8\x10\xa9\xa8T\x80\xe3Q	\xa8Z\xc1)\x10\x98\x19\xc7\xe9\x9b]\x81I\xa4\xe3\xb8E8'\x8ai\na\xb8\x99ZYN\xd9\xdaO\x06\xdfyU$\xd6\xcd\x86\xb5\x8a\x0e\xff\xc7S\xac\xa7\xc5\xbd\x15g\xa7\xc07\x88\xa8j\xc2\x910c5\x1d\x9e\xa0\xff=\x07v\"\x16\xd9\xee4\\5\x95\x19|V\xba\x14\x9d\x1f\x13\xbf+\xfc\xaf\xbd\x8eMF\xe9/\xd6\x83\xe8\xc3l\x88;\x96\xf1\xd2T{\xeeW]\x92\x9b\xf8\xdf\xe4\x8b&\xbf\x80\xc6\xe1\xf4\x03\xb3\xe9DD\xcc\xc92\xa7=\xe6\xcbI\xf0\x05X\xc0G\xce\x90\xaa\x8a\xfe\x99\xe39\xe3;\x95\xbeNr.zKr\xc3\"\x92\xf3\xea\xdb\xac+\xef\x17*>\xbb\xaa0\xbd\xf2sd\xd5\x0f)\x06\x05\xa3d\xfc;\xdc\x05\x19(\xf4dPL1\xc1mj\x07U\xde\x9e\xa0\xa1\x1fh\xed\x86\x8e	a\x89\x14\xd7\xde\xf8;\na.'\xd2\xbf\x01\x83\x87\xa5\xc5\xc3\xca\x0dU\xd5\xad\x1d\x0c`\x07\xb0J\xbcWV\xeb\nj\xd8\x92*\xdc n\xa3b\x0c4<^\x03\x1fh\xc0+\xaeU\x13\xcbZH\xaf\x05Z\xbe\xb7:\xae\xb7\xdcG\x154\xb4\x02\xeeQ\xf6m<{\xa6\x85\xda\xeakEQ\xa4a\xcb[L\xe5\xba|\xcc\xf4\xf8\x9a\xdc\x11\\\xa8\x16\xd1+<`\xa0\xdad\x96\xc7\x08\xb4y@\xeecO\xcf\xe7\x1d=\x8fWz\x1eVR^\xc2cW\x0f2\x93J\xd9)\x17W3\xce\xe8\xb4\x00\x1c\xd7\xa9J\x93\x94\xc4g\xab\x08\xbf\xc7Z\x95]\x86;e\x0c\xfb\x9b>r/Qn\xbc[2\xee\xe8\x18\xdb\xd0{\xdbz\xd8\xb9i\x99/^%\xf6\xf2\xb1:\xea\xed\xbe@\xbfWs)\x126f$\xed\xb0\x01\xe4G\xe8\xe1\xbd\xd8\n\xd2\xc1\xb5\x18\x7f\xde\xbf\xf5\xfb\xa51`\x1b\x1a\x8d\xd1\x98a5\xe5\x8a\xa9gQxT\x0cz\x0b\x9b\x19\x05]\x81\x13\\\xde#g?A4@\xa0eReWPw\xa2\xa0\x16T\x02\x17\n\x88\x94cG\x01\xb5\x90\x1bk\x99z\x1a\xe1\xe6\xd4\x07{\x8b\xd4u\xc9\x9a\xd2@\xf77\x18f\xc2\xdc\xa8_o\xe6UT7\xa3~\xf6\x01\x0cl\xeb\xdfmdm\x8d\xd3\xf9\xd6\x8a\xea\x87\xb9\xb8\x90\xb6\x15\x13\x8c\xe6w%\xba^\xe0%\x9dVJ\x0c zi<sd\xb1d\x14I\xe1\xf5\xf4jM\xa7\xd7\x80\xdd}\xb3\xb3\xa2>|\"%;\x07\x9f\xbf\xa2\x01\xb3\x9f9LL\xdd\x81\xb6\xb4\xa3\\I\xa4\xcf\xc6z\xd1\x00w\xaf\x93\x839\x8e/\x13\xbe\x04\x84T\xd5\xd8\x8d-Qb\x80f\xe4\x15\xae\xaa\xc5\xe9\x8e\xcc.\x9b;-t\xb6\xcd\xe7.(\xf6uL\xe6\xe4u\x82\xea\xf1\xc5\xfc\xdbr\x18\xdf\xc8\x91c\xe7\xe4x\xa5GsMF-\xfd \xea\x0d\xfb\x80\x03\x12\xe3d\x8321\x93D:\xa0\xbb\xc6\xcb\xe31d\xf7\xb84IY\x06R\xb9\x90\x0e\x87@:,\xa4\xd3)\x90NK(eEZ2\xa4\xf2s}\xc4\xd8,\xee\x1f\xf17\xa6#\xedb\xe9<\xd4e\xc0\x96\xe5\x07\xd8C\xc0\x1e\x0e\x1f`O\x01{:\xbd\xc6fW\xa8\xb1\xbdy\x0d\xd5 \xa4\x04\x0c\xeaX)\xf9	\x84\xba\xd3\x01\xdb\xe3\xc6xp\xaei\x89\xda\x04\xdd0`\xc2\xca*\xdcK\x14{y*\n\xff\xb4@\x0c\xaa\xf2\xb7PL\xee\xe2\xc7\xe4\xf8\x8b2,\x89\xc97\x1e\xde\x8e\x16\xad\xab0NE\xf2\xdcwW\xe4\xb4\xe9\xb6\xfd\x95\x9f\xfc\xa5\xc4\xdfj\x8b\xa2(\xb2\x7f\x06\x00PK\x07\x08\xcf\x94\xe7\"G\x05\x00\x00Z\x12\x00\x00PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00G\\\xafP.\xc1\xc1\xd4\xb2\x00\x00\x00\xff\x01\x00\x00\x07\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\x00\x00\x00\x00map.xinUT\x05\x00\x016~\xbe^PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00G\\\xafP\xa7\x82\x8aT\x94\x02\x00\x00\xf1\x06\x00\x00\x08\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\xf0\x00\x00\x00math.xinUT\x05\x00\x016~\xbe^PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00G\\\xafP\xb4v\x1d@\x1f\x00\x00\x00\x18\x00\x00\x00\x06\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\xc3\x03\x00\x00os.xinUT\x05\x00\x016~\xbe^PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\xecYR]X\xa3\x8a\xcf:\x01\x00\x00\x05\x04\x00\x00\x07\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\x1f\x04\x00\x00src.xinUT\x05\x00\x01L\xaa\xd4jPK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00G\\\xafP\x1c\x9ea|N\x01\x00\x00\xba\x03\x00\x00\x08\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\x97\x05\x00\x00stat.xinUT\x05\x00\x016~\xbe^PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x96LR]\x1d\xb5\xc6-:\x04\x00\x00e\x0b\x00\x00\x07\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81$\x07\x00\x00std.xinUT\x05\x00\x01-\x93\xd4jPK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00G\\\xafP+W\x1b\x9aE\x05\x00\x00/\x13\x00\x00\x07\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\x9c\x0b\x00\x00str.xinUT\x05\x00\x016~\xbe^PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x08SR])\xfd\x9a\xd5\x13\x02\x00\x00\x1f\x08\x00\x00\x08\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\x1f\x11\x00\x00test.xinUT\x05\x00\x01Q\x9e\xd4jPK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00G\\\xafP\xcf\x94\xe7\"G\x05\x00\x00Z\x12\x00\x00\x07\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81q\x13\x00\x00vec.xinUT\x05\x00\x016~\xbe^PK\x05\x06\x00\x00\x00\x00	\x00	\x000\x02\x00\x00\xf6\x18\x00\x00\x00\x00"
 		fs.Register(data)
 	}
 	
\ No newline at end of file